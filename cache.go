@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/gcal"
+)
+
+// runCacheCommand runs "cache list", "cache clear [calendarID]" or "cache sync [calendarID]"
+func runCacheCommand(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("cache", flag.ExitOnError)
+	fs.StringVar(&gcal.CacheDir, "cacheDir", gcal.CacheDir, "the directory of cached calendars (default: user cache directory)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: holiday-planner-go cache [-cacheDir dir] list | clear [calendarID] | sync [calendarID]")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	switch fs.Arg(0) {
+	case "list":
+		if err := listCache(); err != nil {
+			log.Fatalf("failed to list cache - %s", err.Error())
+		}
+	case "clear":
+		if err := gcal.ClearCache(fs.Arg(1)); err != nil {
+			log.Fatalf("failed to clear cache - %s", err.Error())
+		}
+	case "sync":
+		if gcpAPIKey == "" {
+			log.Fatal("missing environment variable GCP_API_KEY")
+		}
+
+		if err := syncCache(ctx, fs.Arg(1)); err != nil {
+			log.Fatalf("failed to sync cache - %s", describeError(err))
+		}
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+// listCache prints the cached calendars with their fetched ranges and the number of events that start in each
+func listCache() error {
+	entries, err := gcal.ListCache()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CALENDAR\tSTART\tEND\tFETCHED AT\tEVENTS")
+	for _, e := range entries {
+		for _, r := range e.Ranges {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", e.CalendarID, r.Start, r.End, r.FetchedAt.Format("2006-01-02 15:04"), e.CountEvents(r))
+		}
+	}
+
+	return w.Flush()
+}
+
+// syncCache updates a cached calendar, or every cached calendar if calendarID is empty, and prints the changed holidays
+func syncCache(ctx context.Context, calendarID string) error {
+	calendarIDs := []string{calendarID}
+	if calendarID == "" {
+		entries, err := gcal.ListCache()
+		if err != nil {
+			return err
+		}
+
+		calendarIDs = nil
+		for _, e := range entries {
+			calendarIDs = append(calendarIDs, e.CalendarID)
+		}
+	}
+
+	for _, id := range calendarIDs {
+		report, err := gcal.SyncCalendarContext(ctx, gcpAPIKey, id)
+		if err != nil {
+			return err
+		}
+		printSyncReport(report)
+	}
+
+	return nil
+}
+
+// printSyncReport prints the holidays that changed since the last sync of a calendar
+func printSyncReport(report *gcal.SyncReport) {
+	if len(report.Changes) == 0 {
+		fmt.Printf("%s: no changes\n", report.CalendarID)
+		return
+	}
+
+	fmt.Printf("%s: %d changes\n", report.CalendarID, len(report.Changes))
+	for _, c := range report.Changes {
+		fmt.Printf("  %s\n", c)
+	}
+}
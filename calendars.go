@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/gcal"
+	"github.com/jvmistica/holiday-planner-go/pkg/rules"
+)
+
+// runCalendarsCommand runs "calendars list" or "calendars regions <country>"
+func runCalendarsCommand(args []string) {
+	fs := flag.NewFlagSet("calendars", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: holiday-planner-go calendars list | regions <country>")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	switch {
+	case fs.Arg(0) == "list":
+		if err := listCalendars(); err != nil {
+			log.Fatalf("failed to list calendars - %s", err.Error())
+		}
+	case fs.Arg(0) == "regions" && fs.NArg() == 2:
+		if err := listRegions(fs.Arg(1)); err != nil {
+			log.Fatalf("failed to list regions - %s", err.Error())
+		}
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+// listCalendars prints the countries with a public holiday calendar on Google, their languages (-lang),
+// and whether the built-in rules cover them
+func listCalendars() error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COUNTRY\tNAME\tLANGUAGES\tRULES\tCALENDAR ID")
+	for _, c := range gcal.ListHolidayCalendars() {
+		offline := "no"
+		if _, _, err := rules.GetCalendar(c.Country); err == nil {
+			offline = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Country, c.Name, strings.Join(c.Languages, ","), offline, c.ID(gcal.DefaultLanguage))
+	}
+
+	return w.Flush()
+}
+
+// listRegions prints the regions of a country that regional holidays are given for (-country), sorted by code
+func listRegions(country string) error {
+	calendar, err := gcal.GetHolidayCalendar(country)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]string, 0, len(calendar.Regions))
+	for code := range calendar.Regions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REGION\tNAME")
+	for _, code := range codes {
+		fmt.Fprintf(w, "%s\t%s\n", code, calendar.Regions[code])
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/gcal"
+	"github.com/jvmistica/holiday-planner-go/pkg/trello"
+)
+
+// describeError returns the message of an error, led by what to do about it if it is a known API error
+func describeError(err error) string {
+	if hint := getErrorHint(err); hint != "" {
+		return fmt.Sprintf("%s (%s)", hint, err.Error())
+	}
+
+	return err.Error()
+}
+
+// getErrorHint returns what to do about a failed request to Google Calendar or Trello, or an empty string
+func getErrorHint(err error) string {
+	var gcalErr *gcal.APIError
+	if errors.As(err, &gcalErr) {
+		switch gcalErr.Reason {
+		case gcal.ReasonInvalidKey:
+			return "the Google API key is not valid, check GCP_API_KEY"
+		case gcal.ReasonQuotaExceeded:
+			if gcalErr.Retryable() {
+				return "the Google Calendar API rate limit is exceeded, try again in a minute"
+			}
+			return "the Google Calendar API quota is exceeded, try again tomorrow or use -country for offline holidays"
+		case gcal.ReasonCalendarNotFound:
+			if suggestion := gcal.SuggestCalendarID(gcalErr.CalendarID); suggestion != "" {
+				return fmt.Sprintf("calendar ID %q not found, did you mean %q?", gcalErr.CalendarID, suggestion)
+			}
+			return fmt.Sprintf("calendar ID %q not found, check -calendarId", gcalErr.CalendarID)
+		case gcal.ReasonForbidden:
+			return "the Google API key cannot read the calendar, check that the Calendar API is enabled for it"
+		case gcal.ReasonServerError:
+			return "Google Calendar is unavailable, try again later"
+		}
+	}
+
+	var trelloErr *trello.APIError
+	if errors.As(err, &trelloErr) {
+		switch trelloErr.Reason {
+		case trello.ReasonInvalidKey:
+			return "the Trello API key is not valid, check TRELLO_API_KEY"
+		case trello.ReasonInvalidToken:
+			return "the Trello API token is not valid or has expired, check TRELLO_API_TOKEN"
+		case trello.ReasonUnauthorized:
+			return "the Trello API token has no access to the board, check TRELLO_API_TOKEN and -trelloBoardId"
+		case trello.ReasonBoardNotFound:
+			return fmt.Sprintf("Trello board not found, check -trelloBoardId or leave it out to use the board named %q", trello.DefaultBoardName)
+		case trello.ReasonRateLimited:
+			return "the Trello rate limit is exceeded, try again in a minute"
+		case trello.ReasonServerError:
+			return "Trello is unavailable, try again later"
+		}
+	}
+
+	return ""
+}
@@ -1,24 +1,45 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/jvmistica/holiday-planner-go/pkg/gcal"
+	"github.com/jvmistica/holiday-planner-go/pkg/ics"
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/jvmistica/holiday-planner-go/pkg/rules"
 	"github.com/jvmistica/holiday-planner-go/pkg/suggestion"
+	"github.com/jvmistica/holiday-planner-go/pkg/trello"
 )
 
 var (
 	defaultCalendarID = "en.austrian#holiday@group.v.calendar.google.com"
+	defaultCountry    = "AT"
+	sourceGoogle      = "google"
+	sourceRules       = "rules"
+	sourceICS         = "ics"
 	gcpAPIKey         = os.Getenv("GCP_API_KEY")
 )
 
-func init() {
-	gcpAPIKey := os.Getenv("GCP_API_KEY")
-	if gcpAPIKey == "" {
+// checkEnv exits if an environment variable needed to generate suggestions is missing.
+// The GCP API key is only needed to query Google Calendar, and the Trello API key and token to create a board.
+func checkEnv(needsGCP, needsTrello bool) {
+	if needsGCP && gcpAPIKey == "" {
 		log.Fatal("missing environment variable GCP_API_KEY")
 	}
 
+	if !needsTrello {
+		return
+	}
+
 	trelloAPIKey := os.Getenv("TRELLO_API_KEY")
 	if trelloAPIKey == "" {
 		log.Fatal("missing environment variable TRELLO_API_KEY")
@@ -30,13 +51,306 @@ func init() {
 	}
 }
 
+// notifyContext returns a context that is canceled on SIGINT or SIGTERM, so that requests in flight are aborted
+// and a failed Trello run is rolled back. A second signal stops the program at once.
+func notifyContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return ctx, stop
+}
+
 func main() {
-	calendarID := flag.String("calendarId", defaultCalendarID, "the calendarID")
+	ctx, stop := notifyContext()
+	defer stop()
+
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		runCacheCommand(ctx, os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "undo" {
+		runUndoCommand(ctx, os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "calendars" {
+		runCalendarsCommand(os.Args[2:])
+		return
+	}
+
+	calendarID := flag.String("calendarId", "", "the ID of the Google calendar (default: the public holiday calendar of -country and -lang, or \""+defaultCalendarID+"\")")
+	source := flag.String("source", sourceGoogle, "where holidays come from: \"google\", \"rules\" or \"ics\"")
+	country := flag.String("country", "", "the country, or the region whose regional holidays are included (e.g. \"AT\", \"DE-BY\"), see \"calendars list\" and \"calendars regions\"")
+	overridesPath := flag.String("holidayOverrides", "", "path to a JSON file with holidays that replace or add to those of the source, e.g. regional holidays")
+	lang := flag.String("lang", "", "the language of the names of holidays from Google (e.g. \"de\", default: \"en\")")
+	icsLocation := flag.String("ics", "", "the path or URL of an iCalendar (.ics) file with the holidays")
+	output := flag.String("output", suggestion.SinkTrello, "comma-separated outputs of the suggestions: \"trello\", \"table\", \"json\", \"csv\", \"markdown\" or \"ics\", each with an optional file path (e.g. \"table,json:plan.json\", default file: standard output)")
+	boardID := flag.String("trelloBoardId", "", "the ID of the Trello board to sync (default: the board named \"Holidays\")")
+	newBoard := flag.Bool("newBoard", false, "create a new Trello board instead of syncing the existing one")
+	keepPartial := flag.Bool("keepPartial", false, "keep what was created on Trello if publishing fails, so that the next run resumes it, instead of removing it")
+	flag.StringVar(&trello.RunsDir, "runsDir", trello.RunsDir, "the directory of the Trello runs, which can be undone (default: user cache directory)")
+	labels := suggestion.DefaultLabelOptions()
+	flag.Float64Var(&labels.ExcellentRatio, "excellentRatio", labels.ExcellentRatio, "the least days off per leave of a Trello card labeled \"excellent\"")
+	flag.Float64Var(&labels.GoodRatio, "goodRatio", labels.GoodRatio, "the least days off per leave of a Trello card labeled \"good\", cards with less are \"fair\"")
+	flag.StringVar(&labels.Period, "labelPeriod", labels.Period, "the period that Trello cards are labeled with: \"season\", \"quarter\" or \"none\"")
 	start := flag.String("start", "", "the start date")
 	end := flag.String("end", "", "the end date")
+	observances := flag.String("observances", "", "comma-separated observances to treat as days off, by name or date (e.g. \"Christmas Eve,12-31\")")
+	budget := flag.Int("budget", 0, "the number of leaves available, to pick the combination of suggestions with the most days off")
+	weekend := flag.String("weekend", planner.DefaultWeekend, "comma-separated non-working weekdays, with \"|\" between alternating weeks (e.g. \"fri,sat\", \"sat,sun|fri,sat,sun\")")
+	weekendAnchor := flag.String("weekendAnchor", "", "the date (YYYY-MM-DD) in the first week of alternating weekends, required for them")
+	workWeekConfig := flag.String("workWeekConfig", "", "path to a JSON file with the weekend and anchor, overrides -weekend")
+	opts := planner.DefaultOptions()
+	flag.IntVar(&opts.MinBlockDays, "minBlockDays", opts.MinBlockDays, "the least consecutive free days that make a vacation without leaves")
+	flag.IntVar(&opts.MaxLeaves, "maxLeaves", opts.MaxLeaves, "the most leaves a suggestion can need")
+	flag.Float64Var(&opts.MinRatio, "minRatio", opts.MinRatio, "the least days off per leave a suggestion must give")
+	flag.IntVar(&opts.MinFreeDays, "minFreeDays", opts.MinFreeDays, "the least days off a suggestion must give on top of its leaves")
+	flag.IntVar(&opts.MinTripDays, "minTripDays", opts.MinTripDays, "the shortest suggestion in days")
+	flag.IntVar(&opts.MaxTripDays, "maxTripDays", opts.MaxTripDays, "the longest suggestion in days, 0 for no limit")
+	flag.StringVar(&gcal.CacheDir, "cacheDir", gcal.CacheDir, "the directory of cached calendars (default: user cache directory)")
+	flag.DurationVar(&gcal.CacheTTL, "cacheTTL", gcal.CacheTTL, "how long cached holidays are reused, 0 to never expire")
+	sync := flag.Bool("sync", false, "update the cached holidays with the changes since they were fetched and print them")
 	flag.Parse()
 
-	if err := suggestion.GenerateSuggestions(gcpAPIKey, *start, *end, *calendarID); err != nil {
-		log.Fatalf("failed to generate suggestions - %s", err.Error())
+	outputs, err := parseOutputs(*output)
+	if err != nil {
+		log.Fatalf("invalid output - %s", err.Error())
+	}
+
+	if *source == sourceGoogle {
+		*calendarID, err = getCalendarID(*calendarID, *country, *lang)
+		if err != nil {
+			log.Fatalf("invalid calendar - %s", err.Error())
+		}
+	}
+	checkEnv(*source == sourceGoogle, hasOutput(outputs, suggestion.SinkTrello))
+
+	if err := opts.Validate(); err != nil {
+		log.Fatalf("invalid options - %s", err.Error())
+	}
+
+	if err := labels.Validate(); err != nil {
+		log.Fatalf("invalid labels - %s", err.Error())
+	}
+
+	workWeek, err := getWorkWeek(*weekend, *weekendAnchor, *workWeekConfig)
+	if err != nil {
+		log.Fatalf("invalid work week - %s", err.Error())
+	}
+	opts.WorkWeek = workWeek
+	opts.Observances = splitList(*observances)
+
+	// leaves of the cards moved to the "Booked" list of the Trello board are already taken
+	if hasOutput(outputs, suggestion.SinkTrello) {
+		takenLeaves, err := trello.GetDefaultClient().GetBookedLeaves(ctx, *boardID, trello.DefaultBoardName)
+		if err != nil {
+			log.Fatalf("failed to get booked leaves - %s", describeError(err))
+		}
+		opts.TakenLeaves = takenLeaves
+	}
+
+	if *sync && *source == sourceGoogle {
+		report, err := gcal.SyncCalendarContext(ctx, gcpAPIKey, *calendarID)
+		if err == nil {
+			printSyncReport(report)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Fatalf("failed to sync cache - %s", describeError(err))
+		}
+	}
+
+	provider, err := getProvider(*source, *calendarID, *country, *icsLocation)
+	if err != nil {
+		log.Fatalf("invalid source - %s", err.Error())
+	}
+
+	if *overridesPath != "" {
+		overrides, err := planner.LoadOverrides(*overridesPath)
+		if err != nil {
+			log.Fatalf("invalid holiday overrides - %s", err.Error())
+		}
+		provider = &planner.OverrideProvider{Provider: provider, Overrides: overrides, Region: getRegion(*country)}
+	}
+
+	sinks, files, err := getSinks(outputs)
+	if err != nil {
+		log.Fatalf("invalid output - %s", err.Error())
+	}
+
+	for _, s := range sinks {
+		if t, ok := s.(*suggestion.TrelloSink); ok {
+			t.BoardID = *boardID
+			t.Sync = !*newBoard
+			t.KeepPartial = *keepPartial
+			t.Labels = labels
+		}
+	}
+
+	err = suggestion.GenerateSuggestionsContext(ctx, provider, *start, *end, opts, *budget, sinks...)
+	for _, f := range files {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Fatalf("failed to generate suggestions - %s", describeError(err))
+	}
+}
+
+// output is a sink of the suggestions and the path of the file it writes to, empty for the standard output
+type output struct {
+	name string
+	path string
+}
+
+// parseOutputs returns the outputs of a comma-separated list of names with an optional file path (e.g. "table,json:plan.json")
+func parseOutputs(value string) ([]*output, error) {
+	var outputs []*output
+	for _, i := range splitList(value) {
+		name, path, _ := strings.Cut(i, ":")
+		name = suggestion.GetSinkName(name)
+		if _, err := suggestion.NewSink(name, nil); err != nil {
+			return nil, err
+		}
+		if hasOutput(outputs, name) {
+			return nil, fmt.Errorf("duplicate output: %s", name)
+		}
+		outputs = append(outputs, &output{name: name, path: path})
+	}
+
+	if len(outputs) == 0 {
+		return nil, errors.New("missing output")
+	}
+
+	return outputs, nil
+}
+
+// hasOutput returns true if a sink is one of the outputs
+func hasOutput(outputs []*output, name string) bool {
+	for _, o := range outputs {
+		if o.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSinks returns the sinks of the outputs, and the files they write to which have to be closed
+func getSinks(outputs []*output) ([]suggestion.Sink, []*os.File, error) {
+	var sinks []suggestion.Sink
+	var files []*os.File
+	for _, o := range outputs {
+		var w io.Writer = os.Stdout
+		if o.path != "" {
+			f, err := os.Create(o.path)
+			if err != nil {
+				for _, i := range files {
+					i.Close()
+				}
+				return nil, nil, err
+			}
+			files = append(files, f)
+			w = f
+		}
+
+		sink, err := suggestion.NewSink(o.name, w)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, files, nil
+}
+
+// splitList splits a comma-separated flag value into its non-empty items
+func splitList(value string) []string {
+	var items []string
+	for _, i := range strings.Split(value, ",") {
+		if i = strings.TrimSpace(i); i != "" {
+			items = append(items, i)
+		}
+	}
+
+	return items
+}
+
+// getWorkWeek returns the work week from a configuration file if given, or from the weekend flags
+func getWorkWeek(weekend, anchor, configPath string) (*planner.WorkWeek, error) {
+	if configPath != "" {
+		return planner.LoadWorkWeek(configPath)
+	}
+
+	return planner.ParseWorkWeek(weekend, anchor)
+}
+
+// getCalendarID returns a Google calendar ID, or the ID of the public holiday calendar of a country and language
+// if it is empty (see gcal.GetCalendarID). Without a country or language, the default calendar is used.
+func getCalendarID(calendarID, country, lang string) (string, error) {
+	if calendarID != "" {
+		return calendarID, nil
+	}
+
+	if country == "" && lang == "" {
+		return defaultCalendarID, nil
+	}
+
+	if country == "" {
+		country = defaultCountry
+	}
+
+	return gcal.GetCalendarID(country, lang)
+}
+
+// getGoogleRegion returns the region code of a country or region code (e.g. "AT-9") for a Google calendar,
+// or an empty string for a country
+func getGoogleRegion(country string) (string, error) {
+	if country == "" {
+		return "", nil
+	}
+
+	calendar, err := gcal.GetHolidayCalendar(country)
+	if err != nil {
+		return "", err
+	}
+
+	return calendar.GetRegion(country)
+}
+
+// getRegion returns the region code of a country or region code, or an empty string for a country
+func getRegion(country string) string {
+	if !strings.Contains(country, "-") {
+		return ""
+	}
+
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// getProvider returns the provider of holidays of a source: a Google Calendar, the built-in rules of a country,
+// or an iCalendar file or URL
+func getProvider(source, calendarID, country, icsLocation string) (planner.HolidayProvider, error) {
+	switch source {
+	case sourceGoogle:
+		region, err := getGoogleRegion(country)
+		if err != nil {
+			return nil, err
+		}
+
+		provider := gcal.NewProvider(gcpAPIKey, calendarID)
+		provider.Region = region
+		return provider, nil
+	case sourceRules:
+		return rules.NewProvider(country)
+	case sourceICS:
+		if icsLocation == "" {
+			return nil, errors.New("missing -ics path or URL")
+		}
+		return ics.NewProvider(icsLocation), nil
+	default:
+		return nil, fmt.Errorf("unknown source: %s", source)
 	}
 }
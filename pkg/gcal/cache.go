@@ -0,0 +1,335 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// CacheDir is the directory of cached calendars, the user cache directory (e.g. $XDG_CACHE_HOME) if empty
+	CacheDir = ""
+	// CacheTTL is how long fetched holidays are reused before they are fetched again, forever if zero
+	CacheTTL = 30 * 24 * time.Hour
+
+	cacheDirName  = "holiday-planner-go"
+	cacheFileExt  = ".json"
+	cacheFileMode = os.FileMode(0o644)
+)
+
+// CacheRange is a date range of a calendar that was fetched from the Calendar API
+type CacheRange struct {
+	Start     string    `json:"start"`
+	End       string    `json:"end"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// CacheEntry contains the details of a cached calendar
+type CacheEntry struct {
+	CalendarID string        `json:"calendarId"`
+	Ranges     []*CacheRange `json:"ranges"`
+	Events     *Events       `json:"events"`
+}
+
+// GetCacheDir returns the directory of cached calendars
+func GetCacheDir() (string, error) {
+	if CacheDir != "" {
+		return CacheDir, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, cacheDirName), nil
+}
+
+// ListCache returns the cached calendars
+func ListCache() ([]*CacheEntry, error) {
+	dir, err := GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+cacheFileExt))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*CacheEntry
+	for _, f := range files {
+		entry, err := readCacheEntry(f)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// ClearCache removes the cache of a calendar, or of every calendar if calendarID is empty
+func ClearCache(calendarID string) error {
+	dir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+
+	if calendarID != "" {
+		err := os.Remove(getCacheFilePath(dir, calendarID))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+cacheFileExt))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getCachedEvents returns the events of a calendar from start to end (inclusive), and only queries
+// the Calendar API for the dates that are not cached yet or have expired
+func getCachedEvents(ctx context.Context, key, calendarID string, start, end time.Time) (*Events, error) {
+	dir, err := GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	filePath := getCacheFilePath(dir, calendarID)
+	entry, err := readCacheEntry(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		entry = &CacheEntry{CalendarID: calendarID, Events: &Events{}}
+	} else if err != nil {
+		return nil, err
+	}
+	entry.expire(time.Now())
+
+	missing := entry.getMissingRanges(start, end)
+	if len(missing) == 0 {
+		log.Print("Skipping GET request..")
+		return entry.Events, nil
+	}
+
+	for _, r := range missing {
+		log.Printf("Initiating GET request for %s to %s..", r.Start, r.End)
+
+		events, err := queryCalendarAPI(ctx, key, calendarID, r.Start, r.End)
+		if err != nil {
+			return nil, err
+		}
+		entry.merge(r, events)
+	}
+
+	if err := writeCacheEntry(ctx, dir, filePath, entry); err != nil {
+		return nil, err
+	}
+
+	return entry.Events, nil
+}
+
+// getCacheFilePath returns the path of the cache file of a calendar
+func getCacheFilePath(dir, calendarID string) string {
+	return filepath.Join(dir, url.PathEscape(calendarID)+cacheFileExt)
+}
+
+// readCacheEntry reads a cached calendar from a file
+func readCacheEntry(filePath string) (*CacheEntry, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	if entry == nil {
+		entry = &CacheEntry{}
+	}
+
+	if entry.Events == nil {
+		entry.Events = &Events{}
+	}
+
+	return entry, nil
+}
+
+// writeCacheEntry writes a cached calendar into a file, creating the cache directory if needed.
+// The file is replaced at once, so an interrupted write leaves the previous cache in place,
+// and it is not replaced if ctx is done.
+func writeCacheEntry(ctx context.Context, dir, filePath string, entry *CacheEntry) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	s, err := json.MarshalIndent(entry, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(s); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(f.Name(), cacheFileMode); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filePath)
+}
+
+// expire removes the ranges that were fetched longer than CacheTTL ago, so that their events are fetched again
+func (c *CacheEntry) expire(now time.Time) {
+	if CacheTTL <= 0 {
+		return
+	}
+
+	var ranges []*CacheRange
+	for _, r := range c.Ranges {
+		if now.Sub(r.FetchedAt) <= CacheTTL {
+			ranges = append(ranges, r)
+		}
+	}
+	c.Ranges = ranges
+}
+
+// getMissingRanges returns the parts of start to end (inclusive) that are not in the cached ranges
+func (c *CacheEntry) getMissingRanges(start, end time.Time) []*CacheRange {
+	ranges := append([]*CacheRange{}, c.Ranges...)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+
+	var missing []*CacheRange
+	cursor := start
+	for _, r := range ranges {
+		if cursor.After(end) {
+			break
+		}
+
+		rangeStart, err := time.Parse(DefaultTimeFormat, r.Start)
+		if err != nil {
+			continue
+		}
+
+		rangeEnd, err := time.Parse(DefaultTimeFormat, r.End)
+		if err != nil || rangeEnd.Before(cursor) {
+			continue
+		}
+
+		if rangeStart.After(cursor) {
+			missing = append(missing, &CacheRange{
+				Start: cursor.Format(DefaultTimeFormat),
+				End:   minTime(rangeStart.AddDate(0, 0, -1), end).Format(DefaultTimeFormat),
+			})
+		}
+		cursor = rangeEnd.AddDate(0, 0, 1)
+	}
+
+	if !cursor.After(end) {
+		missing = append(missing, &CacheRange{
+			Start: cursor.Format(DefaultTimeFormat),
+			End:   end.Format(DefaultTimeFormat),
+		})
+	}
+
+	return missing
+}
+
+// merge replaces the cached events of a range with newly fetched events. Events that start before the range but
+// overlap it are fetched again, so a cached event with the ID of a fetched one is replaced by it.
+func (c *CacheEntry) merge(r *CacheRange, events *Events) {
+	fetched := map[string]bool{}
+	for _, i := range events.Items {
+		if i.ID != "" {
+			fetched[i.ID] = true
+		}
+	}
+
+	var items []*Item
+	for _, i := range c.Events.Items {
+		if date := getItemDate(i); (date < r.Start || date > r.End) && (i.ID == "" || !fetched[i.ID]) {
+			items = append(items, i)
+		}
+	}
+	items = append(items, events.Items...)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return getItemDate(items[i]) < getItemDate(items[j])
+	})
+
+	c.Events.Summary = events.Summary
+	c.Events.TimeZone = events.TimeZone
+	c.Events.NextSyncToken = events.NextSyncToken
+	c.Events.Items = items
+	c.Ranges = append(c.Ranges, &CacheRange{
+		Start:     r.Start,
+		End:       r.End,
+		FetchedAt: time.Now().UTC(),
+	})
+}
+
+// CountEvents returns the number of cached events that start in a range
+func (c *CacheEntry) CountEvents(r *CacheRange) int {
+	var count int
+	for _, i := range c.Events.Items {
+		if date := getItemDate(i); date >= r.Start && date <= r.End {
+			count++
+		}
+	}
+
+	return count
+}
+
+// getItemDate returns the start date (YYYY-MM-DD) of an event
+func getItemDate(item *Item) string {
+	if item.Start.Date != "" {
+		return item.Start.Date
+	}
+
+	date, _, _ := strings.Cut(item.Start.DateTime, "T")
+	return date
+}
+
+// minTime returns the earlier of two times
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
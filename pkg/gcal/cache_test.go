@@ -0,0 +1,296 @@
+package gcal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCacheDir(t *testing.T) {
+	t.Run("configured directory", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = "/tmp/holidays"
+		defer func() {
+			CacheDir = origDir
+		}()
+
+		dir, err := GetCacheDir()
+		assert.Nil(t, err)
+		assert.Equal(t, "/tmp/holidays", dir)
+	})
+
+	t.Run("user cache directory", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", "/tmp/xdg")
+
+		dir, err := GetCacheDir()
+		assert.Nil(t, err)
+		assert.Equal(t, "/tmp/xdg/holiday-planner-go", dir)
+	})
+}
+
+func TestGetCachedEvents(t *testing.T) {
+	start := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query().Get("timeMin")+" "+r.URL.Query().Get("timeMax"))
+		assert.Equal(t, "true", r.URL.Query().Get("singleEvents"))
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"summary": "Holidays in Austria",
+			"items": [{"summary": "Assumption of Mary", "description": "Public holiday", "start": {"date": "2023-08-15"}},
+				{"summary": "National Day", "description": "Public holiday", "start": {"date": "2023-10-26"}}]}`))
+		assert.Nil(t, err)
+	}))
+	defer ts.Close()
+
+	origURL := eventsListURL
+	eventsListURL = ts.URL + "/%s?"
+	defer func() {
+		eventsListURL = origURL
+	}()
+
+	t.Run("fetch only missing ranges", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = t.TempDir()
+		defer func() {
+			CacheDir = origDir
+		}()
+		requests = nil
+
+		events, err := getCachedEvents(context.Background(), "abc", "test", start, end)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(events.Items))
+		assert.Equal(t, []string{"2023-08-01T00:00:00Z 2023-10-01T00:00:00Z"}, requests)
+
+		// the same range is read from the cache
+		_, err = getCachedEvents(context.Background(), "abc", "test", start, end)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(requests))
+
+		// only the dates after the cached range are fetched
+		_, err = getCachedEvents(context.Background(), "abc", "test", start, time.Date(2023, 10, 31, 0, 0, 0, 0, time.UTC))
+		assert.Nil(t, err)
+		assert.Equal(t, "2023-10-01T00:00:00Z 2023-11-01T00:00:00Z", requests[1])
+
+		entries, err := ListCache()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(entries))
+		assert.Equal(t, "test", entries[0].CalendarID)
+		assert.Equal(t, 2, len(entries[0].Ranges))
+		assert.Equal(t, 3, len(entries[0].Events.Items))
+	})
+
+	t.Run("expired range", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = t.TempDir()
+		defer func() {
+			CacheDir = origDir
+		}()
+
+		origTTL := CacheTTL
+		CacheTTL = time.Hour
+		defer func() {
+			CacheTTL = origTTL
+		}()
+		requests = nil
+
+		err := os.WriteFile(filepath.Join(CacheDir, "test.json"), []byte(`{
+			"calendarId": "test",
+			"ranges": [{"start": "2023-08-01", "end": "2023-09-30", "fetchedAt": "2023-07-01T00:00:00Z"}],
+			"events": {"items": [{"summary": "Outdated", "start": {"date": "2023-08-16"}}]}}`), 0o600)
+		assert.Nil(t, err)
+
+		events, err := getCachedEvents(context.Background(), "abc", "test", start, end)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(requests))
+		assert.Equal(t, 2, len(events.Items))
+		assert.Equal(t, "Assumption of Mary", events.Items[0].Summary)
+
+		entries, err := ListCache()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(entries[0].Ranges))
+		assert.True(t, time.Since(entries[0].Ranges[0].FetchedAt) < time.Minute)
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = t.TempDir()
+		defer func() {
+			CacheDir = origDir
+		}()
+
+		origTTL := CacheTTL
+		CacheTTL = 0
+		defer func() {
+			CacheTTL = origTTL
+		}()
+		requests = nil
+
+		err := os.WriteFile(filepath.Join(CacheDir, "test.json"), []byte(`{
+			"calendarId": "test",
+			"ranges": [{"start": "2023-08-01", "end": "2023-09-30", "fetchedAt": "2023-07-01T00:00:00Z"}],
+			"events": {"items": [{"summary": "Outdated", "start": {"date": "2023-08-16"}}]}}`), 0o600)
+		assert.Nil(t, err)
+
+		events, err := getCachedEvents(context.Background(), "abc", "test", start, end)
+		assert.Nil(t, err)
+		assert.Nil(t, requests)
+		assert.Equal(t, "Outdated", events.Items[0].Summary)
+	})
+
+	t.Run("error writing cache", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "file")
+		err := os.WriteFile(filePath, []byte(``), 0o600)
+		assert.Nil(t, err)
+
+		origDir := CacheDir
+		CacheDir = filePath
+		defer func() {
+			CacheDir = origDir
+		}()
+
+		events, err := getCachedEvents(context.Background(), "abc", "test", start, end)
+		assert.NotNil(t, err)
+		assert.Nil(t, events)
+	})
+}
+
+func TestWriteCacheEntry(t *testing.T) {
+	dir := t.TempDir()
+	filePath := getCacheFilePath(dir, "test")
+	entry := &CacheEntry{CalendarID: "test", Events: &Events{Items: []*Item{{ID: "a", Summary: "New Year's Day"}}}}
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := writeCacheEntry(ctx, dir, filePath, entry)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// neither the cache file nor its temporary file are left behind
+		files, err := os.ReadDir(dir)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(files))
+	})
+
+	t.Run("successful", func(t *testing.T) {
+		err := writeCacheEntry(context.Background(), dir, filePath, entry)
+		assert.Nil(t, err)
+
+		cached, err := readCacheEntry(filePath)
+		assert.Nil(t, err)
+		assert.Equal(t, "New Year's Day", cached.Events.Items[0].Summary)
+
+		info, err := os.Stat(filePath)
+		assert.Nil(t, err)
+		assert.Equal(t, cacheFileMode, info.Mode().Perm())
+
+		files, err := os.ReadDir(dir)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(files))
+	})
+}
+
+func TestGetMissingRanges(t *testing.T) {
+	entry := &CacheEntry{Ranges: []*CacheRange{
+		{Start: "2023-10-01", End: "2023-10-31"},
+		{Start: "2023-06-01", End: "2023-06-30"},
+		{Start: "2023-07-01", End: "2023-07-15"},
+	}}
+
+	tests := []struct {
+		start    string
+		end      string
+		expected []string
+	}{
+		{start: "2023-06-10", end: "2023-07-10", expected: nil},
+		{start: "2023-05-01", end: "2023-06-15", expected: []string{"2023-05-01 2023-05-31"}},
+		{start: "2023-07-01", end: "2023-12-31", expected: []string{"2023-07-16 2023-09-30", "2023-11-01 2023-12-31"}},
+		{start: "2023-08-01", end: "2023-08-31", expected: []string{"2023-08-01 2023-08-31"}},
+	}
+
+	for _, tt := range tests {
+		start, err := time.Parse(DefaultTimeFormat, tt.start)
+		assert.Nil(t, err)
+
+		end, err := time.Parse(DefaultTimeFormat, tt.end)
+		assert.Nil(t, err)
+
+		var missing []string
+		for _, r := range entry.getMissingRanges(start, end) {
+			missing = append(missing, r.Start+" "+r.End)
+		}
+		assert.Equal(t, tt.expected, missing, tt.start)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	entry := &CacheEntry{Events: &Events{Items: []*Item{
+		{ID: "1", Summary: "New Year's Day", Start: EventTime{Date: "2024-01-01"}},
+		{ID: "2", Summary: "Summer break", Start: EventTime{Date: "2024-06-28"}, End: &EventTime{Date: "2024-07-06"}},
+		{Summary: "Company day", Start: EventTime{Date: "2024-06-30"}},
+	}}}
+
+	// the summer break starts before the range but overlaps it, so it is fetched again
+	r := &CacheRange{Start: "2024-07-01", End: "2024-07-31"}
+	entry.merge(r, &Events{Items: []*Item{
+		{ID: "2", Summary: "Summer holidays", Start: EventTime{Date: "2024-06-28"}, End: &EventTime{Date: "2024-07-06"}},
+		{ID: "3", Summary: "Staff outing", Start: EventTime{Date: "2024-07-12"}},
+	}})
+
+	var summaries []string
+	for _, i := range entry.Events.Items {
+		summaries = append(summaries, i.Summary)
+	}
+	assert.Equal(t, []string{"New Year's Day", "Summer holidays", "Company day", "Staff outing"}, summaries)
+	assert.Equal(t, 1, len(entry.Ranges))
+
+	// merging the same range again does not duplicate events
+	entry.merge(r, &Events{Items: []*Item{
+		{ID: "2", Summary: "Summer holidays", Start: EventTime{Date: "2024-06-28"}, End: &EventTime{Date: "2024-07-06"}},
+		{ID: "3", Summary: "Staff outing", Start: EventTime{Date: "2024-07-12"}},
+	}})
+	assert.Equal(t, 4, len(entry.Events.Items))
+
+	assert.Equal(t, 1, entry.CountEvents(r))
+	assert.Equal(t, 2, entry.CountEvents(&CacheRange{Start: "2024-06-01", End: "2024-06-30"}))
+}
+
+func TestClearCache(t *testing.T) {
+	origDir := CacheDir
+	CacheDir = t.TempDir()
+	defer func() {
+		CacheDir = origDir
+	}()
+
+	for _, id := range []string{"en.austrian#holiday@group.v.calendar.google.com", "en.german#holiday@group.v.calendar.google.com"} {
+		err := writeCacheEntry(context.Background(), CacheDir, getCacheFilePath(CacheDir, id), &CacheEntry{CalendarID: id, Events: &Events{}})
+		assert.Nil(t, err)
+	}
+
+	err := ClearCache("en.austrian#holiday@group.v.calendar.google.com")
+	assert.Nil(t, err)
+
+	entries, err := ListCache()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "en.german#holiday@group.v.calendar.google.com", entries[0].CalendarID)
+
+	err = ClearCache("not-cached")
+	assert.Nil(t, err)
+
+	err = ClearCache("")
+	assert.Nil(t, err)
+
+	entries, err = ListCache()
+	assert.Nil(t, err)
+	assert.Nil(t, entries)
+}
@@ -0,0 +1,251 @@
+package gcal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// DefaultLanguage is the language of the names of holidays if none is given
+	DefaultLanguage = "en"
+
+	calendarIDSuffix = "#holiday@group.v.calendar.google.com"
+	regionSeparator  = regexp.MustCompile(`\s*(,|&|\band\b)\s*`)
+)
+
+// HolidayCalendar is the public holiday calendar of a country on Google Calendar, available in several languages
+type HolidayCalendar struct {
+	// Country is the country code (ISO 3166-1 alpha-2)
+	Country string
+	Name    string
+	// Languages contains the languages (ISO 639-1) of the names of the holidays, English first
+	Languages []string
+	// Regions contains the English names of the regions that regional holidays are given for, by region code (ISO 3166-2)
+	Regions map[string]string
+	// slug is the part of the calendar ID after the language (e.g. "austrian")
+	slug string
+}
+
+// holidayCalendars contains the public holiday calendars of Google sorted by country code. Older calendars
+// are named after the country (e.g. "austrian"), newer ones after its lowercase country code (e.g. "ch").
+var holidayCalendars = []*HolidayCalendar{
+	{Country: "AT", Name: "Austria", Languages: []string{"en", "de"}, Regions: austrianRegions, slug: "austrian"},
+	{Country: "AU", Name: "Australia", Languages: []string{"en"}, slug: "australian"},
+	{Country: "BR", Name: "Brazil", Languages: []string{"en", "pt"}, slug: "brazilian"},
+	{Country: "CA", Name: "Canada", Languages: []string{"en", "fr"}, slug: "canadian"},
+	{Country: "CH", Name: "Switzerland", Languages: []string{"en", "de", "fr", "it"}, Regions: swissRegions, slug: "ch"},
+	{Country: "CN", Name: "China", Languages: []string{"en", "zh"}, slug: "china"},
+	{Country: "DE", Name: "Germany", Languages: []string{"en", "de"}, Regions: germanRegions, slug: "german"},
+	{Country: "DK", Name: "Denmark", Languages: []string{"en", "da"}, slug: "danish"},
+	{Country: "ES", Name: "Spain", Languages: []string{"en", "es"}, slug: "spain"},
+	{Country: "FI", Name: "Finland", Languages: []string{"en", "fi"}, slug: "finnish"},
+	{Country: "FR", Name: "France", Languages: []string{"en", "fr"}, slug: "french"},
+	{Country: "GB", Name: "United Kingdom", Languages: []string{"en"}, slug: "uk"},
+	{Country: "GR", Name: "Greece", Languages: []string{"en", "el"}, slug: "greek"},
+	{Country: "HK", Name: "Hong Kong", Languages: []string{"en", "zh"}, slug: "hong_kong"},
+	{Country: "ID", Name: "Indonesia", Languages: []string{"en", "id"}, slug: "indonesian"},
+	{Country: "IE", Name: "Ireland", Languages: []string{"en"}, slug: "irish"},
+	{Country: "IN", Name: "India", Languages: []string{"en", "hi"}, slug: "indian"},
+	{Country: "IT", Name: "Italy", Languages: []string{"en", "it"}, slug: "italian"},
+	{Country: "JP", Name: "Japan", Languages: []string{"en", "ja"}, slug: "japanese"},
+	{Country: "KR", Name: "South Korea", Languages: []string{"en", "ko"}, slug: "south_korea"},
+	{Country: "MX", Name: "Mexico", Languages: []string{"en", "es"}, slug: "mexican"},
+	{Country: "MY", Name: "Malaysia", Languages: []string{"en", "ms"}, slug: "malaysia"},
+	{Country: "NL", Name: "Netherlands", Languages: []string{"en", "nl"}, slug: "dutch"},
+	{Country: "NO", Name: "Norway", Languages: []string{"en", "no"}, slug: "norwegian"},
+	{Country: "NZ", Name: "New Zealand", Languages: []string{"en"}, slug: "new_zealand"},
+	{Country: "PH", Name: "Philippines", Languages: []string{"en", "fil"}, slug: "philippines"},
+	{Country: "PL", Name: "Poland", Languages: []string{"en", "pl"}, slug: "polish"},
+	{Country: "PT", Name: "Portugal", Languages: []string{"en", "pt"}, slug: "portuguese"},
+	{Country: "RU", Name: "Russia", Languages: []string{"en", "ru"}, slug: "russian"},
+	{Country: "SE", Name: "Sweden", Languages: []string{"en", "sv"}, slug: "swedish"},
+	{Country: "SG", Name: "Singapore", Languages: []string{"en", "zh"}, slug: "singapore"},
+	{Country: "TW", Name: "Taiwan", Languages: []string{"en", "zh"}, slug: "taiwan"},
+	{Country: "US", Name: "United States", Languages: []string{"en", "es"}, slug: "usa"},
+	{Country: "VN", Name: "Vietnam", Languages: []string{"en", "vi"}, slug: "vietnamese"},
+	{Country: "ZA", Name: "South Africa", Languages: []string{"en"}, slug: "sa"},
+}
+
+var austrianRegions = map[string]string{
+	"AT-1": "Burgenland",
+	"AT-2": "Carinthia",
+	"AT-3": "Lower Austria",
+	"AT-4": "Upper Austria",
+	"AT-5": "Salzburg",
+	"AT-6": "Styria",
+	"AT-7": "Tyrol",
+	"AT-8": "Vorarlberg",
+	"AT-9": "Vienna",
+}
+
+var germanRegions = map[string]string{
+	"DE-BB": "Brandenburg",
+	"DE-BE": "Berlin",
+	"DE-BW": "Baden-Württemberg",
+	"DE-BY": "Bavaria",
+	"DE-HB": "Bremen",
+	"DE-HE": "Hesse",
+	"DE-HH": "Hamburg",
+	"DE-MV": "Mecklenburg-Vorpommern",
+	"DE-NI": "Lower Saxony",
+	"DE-NW": "North Rhine-Westphalia",
+	"DE-RP": "Rhineland-Palatinate",
+	"DE-SH": "Schleswig-Holstein",
+	"DE-SL": "Saarland",
+	"DE-SN": "Saxony",
+	"DE-ST": "Saxony-Anhalt",
+	"DE-TH": "Thuringia",
+}
+
+var swissRegions = map[string]string{
+	"CH-AG": "Aargau",
+	"CH-AI": "Appenzell Innerrhoden",
+	"CH-AR": "Appenzell Ausserrhoden",
+	"CH-BE": "Bern",
+	"CH-BL": "Basel-Landschaft",
+	"CH-BS": "Basel-Stadt",
+	"CH-FR": "Fribourg",
+	"CH-GE": "Geneva",
+	"CH-GL": "Glarus",
+	"CH-GR": "Graubünden",
+	"CH-JU": "Jura",
+	"CH-LU": "Lucerne",
+	"CH-NE": "Neuchâtel",
+	"CH-NW": "Nidwalden",
+	"CH-OW": "Obwalden",
+	"CH-SG": "St. Gallen",
+	"CH-SH": "Schaffhausen",
+	"CH-SO": "Solothurn",
+	"CH-SZ": "Schwyz",
+	"CH-TG": "Thurgau",
+	"CH-TI": "Ticino",
+	"CH-UR": "Uri",
+	"CH-VD": "Vaud",
+	"CH-VS": "Valais",
+	"CH-ZG": "Zug",
+	"CH-ZH": "Zurich",
+}
+
+// ListHolidayCalendars returns the public holiday calendars of Google sorted by country code
+func ListHolidayCalendars() []*HolidayCalendar {
+	return holidayCalendars
+}
+
+// GetHolidayCalendar returns the public holiday calendar of a country or of the country of a region code (e.g. "DE-BY")
+func GetHolidayCalendar(code string) (*HolidayCalendar, error) {
+	country, _, _ := strings.Cut(strings.ToUpper(strings.TrimSpace(code)), "-")
+	for _, c := range holidayCalendars {
+		if c.Country == country {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown country: %s", country)
+}
+
+// GetCalendarID returns the ID of the public holiday calendar of a country in a language, DefaultLanguage if empty
+// (e.g. "AT" and "de" give "de.austrian#holiday@group.v.calendar.google.com")
+func GetCalendarID(country, lang string) (string, error) {
+	c, err := GetHolidayCalendar(country)
+	if err != nil {
+		return "", err
+	}
+
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+
+	if !c.hasLanguage(lang) {
+		return "", fmt.Errorf("unsupported language for %s: %s (available: %s)", c.Country, lang, strings.Join(c.Languages, ", "))
+	}
+
+	return c.ID(lang), nil
+}
+
+// GetRegion returns the region code (ISO 3166-2) of a region code or the country code of the calendar,
+// or an empty string for the country
+func (c *HolidayCalendar) GetRegion(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == c.Country {
+		return "", nil
+	}
+
+	if _, ok := c.Regions[code]; !ok {
+		return "", fmt.Errorf("unknown region: %s", code)
+	}
+
+	return code, nil
+}
+
+// getRegionCode returns the region code of the English name of a region of the calendar (e.g. "Vienna"),
+// or an empty string if there is none
+func (c *HolidayCalendar) getRegionCode(name string) string {
+	for code, n := range c.Regions {
+		if strings.EqualFold(n, name) {
+			return code
+		}
+	}
+
+	return ""
+}
+
+// getRegionCodes returns the region codes of a list of region names (e.g. "Vienna, Lower Austria and Styria"),
+// without the names that are not regions of the calendar
+func (c *HolidayCalendar) getRegionCodes(names string) []string {
+	var codes []string
+	for _, n := range regionSeparator.Split(names, -1) {
+		if code := c.getRegionCode(strings.TrimSpace(n)); code != "" {
+			codes = append(codes, code)
+		}
+	}
+
+	return codes
+}
+
+// ID returns the ID of the calendar in a language
+func (c *HolidayCalendar) ID(lang string) string {
+	return lang + "." + c.slug + calendarIDSuffix
+}
+
+// hasLanguage checks if the holidays of the calendar are named in a language
+func (c *HolidayCalendar) hasLanguage(lang string) bool {
+	for _, l := range c.Languages {
+		if l == lang {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findHolidayCalendar returns the calendar whose country code, name or slug is a name (e.g. "at", "Austria",
+// "austrian"), or nil if there is none
+func findHolidayCalendar(name string) *HolidayCalendar {
+	for _, c := range holidayCalendars {
+		if strings.EqualFold(name, c.Country) || strings.EqualFold(name, c.Name) || strings.EqualFold(name, c.slug) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// findHolidayCalendarByID returns the public holiday calendar of a calendar ID in any language,
+// or nil if it is not a public holiday calendar (e.g. a company calendar)
+func findHolidayCalendarByID(calendarID string) *HolidayCalendar {
+	name, ok := strings.CutSuffix(calendarID, calendarIDSuffix)
+	if !ok {
+		return nil
+	}
+
+	_, slug, _ := strings.Cut(name, ".")
+	for _, c := range holidayCalendars {
+		if c.slug == slug {
+			return c
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package gcal
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListHolidayCalendars(t *testing.T) {
+	calendars := ListHolidayCalendars()
+	assert.True(t, sort.SliceIsSorted(calendars, func(i, j int) bool {
+		return calendars[i].Country < calendars[j].Country
+	}))
+
+	seen := map[string]bool{}
+	for _, c := range calendars {
+		assert.False(t, seen[c.Country], c.Country)
+		seen[c.Country] = true
+		assert.Equal(t, DefaultLanguage, c.Languages[0], c.Country)
+	}
+}
+
+func TestGetCalendarID(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		lang     string
+		expected string
+		err      string
+	}{
+		{name: "default language", country: "AT", expected: "en.austrian#holiday@group.v.calendar.google.com"},
+		{name: "language", country: "at", lang: "DE", expected: "de.austrian#holiday@group.v.calendar.google.com"},
+		{name: "region", country: "DE-BY", lang: "de", expected: "de.german#holiday@group.v.calendar.google.com"},
+		{name: "unknown country", country: "XX", err: "unknown country: XX"},
+		{name: "unsupported language", country: "AT", lang: "fr", err: "unsupported language for AT: fr (available: en, de)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := GetCalendarID(tc.country, tc.lang)
+			if tc.err != "" {
+				assert.Equal(t, tc.err, err.Error())
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, id)
+		})
+	}
+}
+
+func TestHolidayCalendarRegions(t *testing.T) {
+	calendar, err := GetHolidayCalendar("at-9")
+	assert.Nil(t, err)
+	assert.Equal(t, "AT", calendar.Country)
+
+	region, err := calendar.GetRegion("at-9")
+	assert.Nil(t, err)
+	assert.Equal(t, "AT-9", region)
+
+	region, err = calendar.GetRegion("AT")
+	assert.Nil(t, err)
+	assert.Equal(t, "", region)
+
+	_, err = calendar.GetRegion("AT-10")
+	assert.Equal(t, "unknown region: AT-10", err.Error())
+
+	assert.Equal(t, []string{"AT-9", "AT-3"}, calendar.getRegionCodes("Vienna and lower austria, Atlantis"))
+
+	assert.Equal(t, calendar, findHolidayCalendarByID("de.austrian#holiday@group.v.calendar.google.com"))
+	assert.Nil(t, findHolidayCalendarByID("en.atlantis#holiday@group.v.calendar.google.com"))
+	assert.Nil(t, findHolidayCalendarByID("company@group.calendar.google.com"))
+}
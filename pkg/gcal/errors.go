@@ -0,0 +1,138 @@
+package gcal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Reason is the cause of a failed request to the Calendar API
+type Reason string
+
+var (
+	ReasonInvalidKey       Reason = "invalid key"
+	ReasonQuotaExceeded    Reason = "quota exceeded"
+	ReasonCalendarNotFound Reason = "calendar not found"
+	ReasonForbidden        Reason = "forbidden"
+	ReasonServerError      Reason = "server error"
+	ReasonUnknown          Reason = "unknown"
+
+	// rateLimitReasons are the reasons of Google for rate limits that pass, unlike daily limits
+	rateLimitReasons = map[string]bool{"rateLimitExceeded": true, "userRateLimitExceeded": true}
+	quotaReasons     = map[string]bool{"rateLimitExceeded": true, "userRateLimitExceeded": true, "quotaExceeded": true, "dailyLimitExceeded": true}
+)
+
+// APIError is the error of a request to the Calendar API that failed with a status code
+type APIError struct {
+	CalendarID string
+	StatusCode int
+	Reason     Reason
+	// GoogleReason is the reason of the error in the response (e.g. "dailyLimitExceeded"), if it had one
+	GoogleReason string
+	Message      string
+}
+
+// errorResponse is the structure of an error response of a Google API
+type errorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Errors  []struct {
+			Domain  string `json:"domain"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+		Details []struct {
+			Reason string `json:"reason"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (e *APIError) Error() string {
+	s := fmt.Sprintf("failed to get events of calendar %s - status code: %d", e.CalendarID, e.StatusCode)
+	if e.Message != "" {
+		s += " - " + e.Message
+	}
+
+	return s
+}
+
+// Retryable checks if the request may succeed if it is sent again later. Daily limits are not retryable.
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError ||
+		rateLimitReasons[e.GoogleReason]
+}
+
+// newAPIError returns the error of a request for the events of a calendar that failed with a status code,
+// from the {"error": {...}} envelope of its response body
+func newAPIError(calendarID string, statusCode int, body []byte) *APIError {
+	e := &APIError{CalendarID: calendarID, StatusCode: statusCode}
+
+	var res errorResponse
+	if err := json.Unmarshal(body, &res); err == nil {
+		e.Message = res.Error.Message
+		for _, i := range res.Error.Errors {
+			if i.Reason != "" {
+				e.GoogleReason = i.Reason
+				break
+			}
+		}
+
+		// an invalid key is only told apart from other bad requests by the details
+		for _, d := range res.Error.Details {
+			if d.Reason == "API_KEY_INVALID" {
+				e.GoogleReason = "keyInvalid"
+			}
+		}
+	}
+	e.Reason = getReason(statusCode, e.GoogleReason)
+
+	return e
+}
+
+// getReason returns the cause of a request that failed with a status code and a reason of Google
+func getReason(statusCode int, googleReason string) Reason {
+	switch {
+	case googleReason == "keyInvalid" || googleReason == "keyExpired":
+		return ReasonInvalidKey
+	case quotaReasons[googleReason] || statusCode == http.StatusTooManyRequests:
+		return ReasonQuotaExceeded
+	case statusCode == http.StatusNotFound:
+		return ReasonCalendarNotFound
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ReasonForbidden
+	case statusCode >= http.StatusInternalServerError:
+		return ReasonServerError
+	default:
+		return ReasonUnknown
+	}
+}
+
+// SuggestCalendarID returns the ID of the public holiday calendar that a mistyped calendar ID (e.g. "austrian",
+// "de.AT", "en.austrian#holiday") most likely meant, or an empty string if it cannot tell
+func SuggestCalendarID(calendarID string) string {
+	name, _, _ := strings.Cut(strings.TrimSpace(calendarID), "#")
+	name, _, _ = strings.Cut(name, "@")
+
+	lang := DefaultLanguage
+	if l, n, ok := strings.Cut(name, "."); ok {
+		lang, name = strings.ToLower(l), n
+	}
+
+	c := findHolidayCalendar(name)
+	if c == nil {
+		return ""
+	}
+
+	if !c.hasLanguage(lang) {
+		lang = DefaultLanguage
+	}
+
+	if suggestion := c.ID(lang); suggestion != calendarID {
+		return suggestion
+	}
+
+	return ""
+}
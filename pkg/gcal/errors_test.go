@@ -0,0 +1,113 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name              string
+		status            int
+		body              string
+		expectedReason    Reason
+		expectedMessage   string
+		expectedRetryable bool
+	}{
+		{
+			name:   "invalid key",
+			status: http.StatusBadRequest,
+			body: `{"error": {"code": 400, "message": "API key not valid. Please pass a valid API key.",
+				"errors": [{"domain": "global", "reason": "badRequest"}], "status": "INVALID_ARGUMENT",
+				"details": [{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "API_KEY_INVALID"}]}}`,
+			expectedReason:  ReasonInvalidKey,
+			expectedMessage: "API key not valid. Please pass a valid API key.",
+		},
+		{
+			name:   "daily limit",
+			status: http.StatusForbidden,
+			body: `{"error": {"code": 403, "message": "Daily Limit Exceeded",
+				"errors": [{"domain": "usageLimits", "reason": "dailyLimitExceeded"}]}}`,
+			expectedReason:  ReasonQuotaExceeded,
+			expectedMessage: "Daily Limit Exceeded",
+		},
+		{
+			name:   "rate limit",
+			status: http.StatusForbidden,
+			body: `{"error": {"code": 403, "message": "Rate Limit Exceeded",
+				"errors": [{"domain": "usageLimits", "reason": "rateLimitExceeded"}]}}`,
+			expectedReason:    ReasonQuotaExceeded,
+			expectedMessage:   "Rate Limit Exceeded",
+			expectedRetryable: true,
+		},
+		{
+			name:            "calendar not found",
+			status:          http.StatusNotFound,
+			body:            `{"error": {"code": 404, "message": "Not Found", "errors": [{"domain": "global", "reason": "notFound"}]}}`,
+			expectedReason:  ReasonCalendarNotFound,
+			expectedMessage: "Not Found",
+		},
+		{name: "forbidden", status: http.StatusForbidden, body: `{"error": {"code": 403, "message": "Forbidden"}}`, expectedReason: ReasonForbidden, expectedMessage: "Forbidden"},
+		{name: "server error without body", status: http.StatusServiceUnavailable, expectedReason: ReasonServerError, expectedRetryable: true},
+		{name: "unknown", status: http.StatusBadRequest, body: "bad request", expectedReason: ReasonUnknown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newAPIError("test", tc.status, []byte(tc.body))
+			assert.Equal(t, tc.expectedReason, e.Reason)
+			assert.Equal(t, tc.expectedMessage, e.Message)
+			assert.Equal(t, tc.expectedRetryable, e.Retryable())
+		})
+	}
+
+	t.Run("returned by requests", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error": {"code": 404, "message": "Not Found", "errors": [{"reason": "notFound"}]}}`))
+			assert.Nil(t, err)
+		}))
+		defer ts.Close()
+
+		origURL := eventsListURL
+		eventsListURL = ts.URL + "/%s?"
+		defer func() {
+			eventsListURL = origURL
+		}()
+
+		_, err := queryCalendarAPI(context.Background(), "abc", "austrian", "2024-01-01", "2024-12-31")
+		assert.Equal(t, "failed to get events of calendar austrian - status code: 404 - Not Found", err.Error())
+
+		var e *APIError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, ReasonCalendarNotFound, e.Reason)
+		assert.Equal(t, "austrian", e.CalendarID)
+	})
+}
+
+func TestSuggestCalendarID(t *testing.T) {
+	tests := []struct {
+		calendarID string
+		expected   string
+	}{
+		{calendarID: "austrian", expected: "en.austrian#holiday@group.v.calendar.google.com"},
+		{calendarID: "en.Austrian#holiday", expected: "en.austrian#holiday@group.v.calendar.google.com"},
+		{calendarID: "de.austrian@group.v.calendar.google.com", expected: "de.austrian#holiday@group.v.calendar.google.com"},
+		{calendarID: "DE.AT", expected: "de.austrian#holiday@group.v.calendar.google.com"},
+		{calendarID: "fr.germany", expected: "en.german#holiday@group.v.calendar.google.com"},
+		{calendarID: "en.austrian#holiday@group.v.calendar.google.com", expected: ""},
+		{calendarID: "en.atlantis#holiday@group.v.calendar.google.com", expected: ""},
+		{calendarID: "", expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.calendarID, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SuggestCalendarID(tc.calendarID))
+		})
+	}
+}
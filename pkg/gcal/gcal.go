@@ -1,257 +1,323 @@
 package gcal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
-	"os"
-	"sort"
+	"strings"
 	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/jvmistica/holiday-planner-go/pkg/transport"
 )
 
 var (
-	DefaultTimeFormat = "2006-01-02"
-	DefaultFilePath   = "./pkg/gcal/data/%s.json"
+	DefaultTimeFormat = planner.DefaultTimeFormat
+
+	// HTTPClient is the HTTP client of the Calendar API, which limits the rate of requests and retries the ones
+	// that failed with a 429 or a 5xx
+	HTTPClient = newDefaultHTTPClient()
 
-	defaultMinDaysWithoutLeave = 3
-	eventsListURL              = "https://www.googleapis.com/calendar/v3/calendars/%s/events?"
+	eventsListURL     = "https://www.googleapis.com/calendar/v3/calendars/%s/events?"
+	rateLimit         = 10
+	rateLimitInterval = time.Second
 )
 
+// newDefaultHTTPClient returns an HTTP client with a rate limit of 10 requests per second and the default retries
+func newDefaultHTTPClient() *http.Client {
+	config := transport.DefaultConfig()
+	config.Rate, config.Interval = rateLimit, rateLimitInterval
+	return transport.NewClient(config)
+}
+
 // Events is the structure of the response from the Google Calendar API
 type Events struct {
 	Summary       string  `json:"summary,omitempty"`
+	TimeZone      string  `json:"timeZone,omitempty"`
+	NextPageToken string  `json:"nextPageToken,omitempty"`
 	NextSyncToken string  `json:"nextSyncToken,omitempty"`
 	Items         []*Item `json:"items,omitempty"`
 }
 
 // Item is the structure of each event
 type Item struct {
-	Summary     string `json:"summary,omitempty"`
-	Description string `json:"description,omitempty"`
-	Start       struct {
-		Date string `json:"date,omitempty"`
-	} `json:"start,omitempty"`
+	ID          string     `json:"id,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Summary     string     `json:"summary,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Start       EventTime  `json:"start,omitempty"`
+	End         *EventTime `json:"end,omitempty"`
 }
 
-// Suggestion contains the details of suggested vacation dates
-type Suggestion struct {
-	Vacation int
-	Leaves   int
-	Start    time.Time
-	End      time.Time
+// EventTime is the start or end of an event, either a date for all-day events or a timestamp (RFC 3339)
+type EventTime struct {
+	Date     string `json:"date,omitempty"`
+	DateTime string `json:"dateTime,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
-// Vacation contains the details of vacation dates (long weekends, etc.)
-type Vacation struct {
-	Start time.Time
-	End   time.Time
-	Count int
+// Provider gets holidays from a Google Calendar, caching them under CacheDir
+type Provider struct {
+	Key        string
+	CalendarID string
+	// Region is the region code (ISO 3166-2) whose regional holidays are included besides the nationwide ones,
+	// only nationwide holidays if empty, like the other providers. Regional holidays are only known for public
+	// holiday calendars with regions (see HolidayCalendar).
+	Region string
 }
 
-// GetCalendarEvents returns all holidays, weekends, and suggested vacation leaves
-func GetCalendarEvents(key, start, end, calendarID string) ([]*Vacation, []*Suggestion, error) {
-	var events *Events
-	filePath := fmt.Sprintf(DefaultFilePath, calendarID)
+// NewProvider returns a provider of the holidays of a Google Calendar (e.g. "en.austrian#holiday@group.v.calendar.google.com")
+func NewProvider(key, calendarID string) *Provider {
+	return &Provider{Key: key, CalendarID: calendarID}
+}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		log.Print("Initiating GET request..")
+// GetHolidays returns the holidays of the calendar from start to end (inclusive), classified by their description
+func (p *Provider) GetHolidays(start, end time.Time) ([]*planner.Holiday, error) {
+	return p.GetHolidaysContext(context.Background(), start, end)
+}
 
-		var err error
-		events, err = queryCalendarAPI(events, key, calendarID, start, end, filePath)
-		if err != nil {
-			return nil, nil, err
+// GetHolidaysContext is GetHolidays with a context that cancels the requests to the Calendar API
+func (p *Provider) GetHolidaysContext(ctx context.Context, start, end time.Time) ([]*planner.Holiday, error) {
+	events, err := getCachedEvents(ctx, p.Key, p.CalendarID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := getHolidays(events, findHolidayCalendarByID(p.CalendarID))
+	if err != nil {
+		return nil, err
+	}
+
+	return planner.FilterRegion(holidays, p.Region), nil
+}
+
+// GetCalendarEvents returns all holidays, weekends, and suggested vacation leaves of a Google Calendar.
+// It is a shortcut for planner.Suggest with a Provider. If opts is nil, planner.DefaultOptions is used.
+func GetCalendarEvents(key, start, end, calendarID string, opts *planner.Options) ([]*planner.Vacation, []*planner.Suggestion, error) {
+	return GetCalendarEventsContext(context.Background(), key, start, end, calendarID, opts)
+}
+
+// GetCalendarEventsContext is GetCalendarEvents with a context that cancels the requests to the Calendar API
+func GetCalendarEventsContext(ctx context.Context, key, start, end, calendarID string, opts *planner.Options) ([]*planner.Vacation, []*planner.Suggestion, error) {
+	return planner.SuggestContext(ctx, NewProvider(key, calendarID), start, end, opts)
+}
+
+// getHolidays returns a list of holidays classified by their kind, with one holiday for each date of multi-day events
+// and for each region of regional holidays of a public holiday calendar, if it is not nil
+func getHolidays(events *Events, calendar *HolidayCalendar) ([]*planner.Holiday, error) {
+	var holidays []*planner.Holiday
+	for _, item := range events.Items {
+		if item.Status == statusCancelled {
+			continue
 		}
-	} else {
-		log.Print("Skipping GET request..")
 
-		data, err := os.ReadFile(filePath)
+		dates, err := item.getDates(events.TimeZone)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
+		}
+
+		regions := item.getRegions(calendar)
+		if len(regions) == 0 {
+			regions = []string{""}
 		}
 
-		if err := json.Unmarshal(data, &events); err != nil {
-			return nil, nil, err
+		for _, d := range dates {
+			for _, r := range regions {
+				holidays = append(holidays, &planner.Holiday{
+					Date:   d,
+					Name:   item.Summary,
+					Kind:   classifyHoliday(item.Description),
+					Region: r,
+				})
+			}
 		}
 	}
 
-	holidays, err := getHolidays(events)
+	return holidays, nil
+}
+
+// getDates returns every date covered by an event. The end date of all-day events is exclusive, and timed events
+// are converted to their time zone, or to the calendar's time zone if they have none.
+func (i *Item) getDates(calendarTimeZone string) ([]time.Time, error) {
+	start, err := i.Start.getTime(calendarTimeZone)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	weekends, err := getWeekends(start, end)
-	if err != nil {
-		return nil, nil, err
+	last := start
+	if i.End != nil && (i.End.Date != "" || i.End.DateTime != "") {
+		end, err := i.End.getTime(calendarTimeZone)
+		if err != nil {
+			return nil, err
+		}
+
+		if i.End.Date != "" {
+			last = end.AddDate(0, 0, -1)
+		} else {
+			last = end.Add(-time.Nanosecond)
+		}
 	}
 
-	freeTime := formatFreeTime(holidays, weekends)
-	vacationWithoutLeaves := getVacationsWithoutLeaves(freeTime)
-	suggestions := getSuggestions(vacationWithoutLeaves)
+	first, lastDate := toDate(start), toDate(last)
+	if lastDate.Before(first) {
+		lastDate = first
+	}
 
-	return vacationWithoutLeaves, suggestions, nil
+	var dates []time.Time
+	for d := first; !d.After(lastDate); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+
+	return dates, nil
 }
 
-// getHolidays returns a map of holidays and their date
-func getHolidays(events *Events) ([]time.Time, error) {
-	var holidays []time.Time
-	for _, item := range events.Items {
-		start, err := time.Parse(DefaultTimeFormat, item.Start.Date)
-		if err != nil {
-			return holidays, err
+// getRegions returns the region codes of a regional holiday of a calendar, from the regions named after "in" in the
+// first line of its description (e.g. "Public holiday in Vienna, Lower Austria") or in parentheses after its summary
+// (e.g. "Epiphany (Bavaria, Saxony-Anhalt)"), or nil if it applies to the whole calendar. Only English names are known.
+func (i *Item) getRegions(calendar *HolidayCalendar) []string {
+	if calendar == nil || len(calendar.Regions) == 0 {
+		return nil
+	}
+
+	description, _, _ := strings.Cut(i.Description, "\n")
+	if _, names, ok := strings.Cut(description, " in "); ok {
+		if regions := calendar.getRegionCodes(names); len(regions) > 0 {
+			return regions
 		}
-		holidays = append(holidays, start)
 	}
 
-	return holidays, nil
+	if start := strings.LastIndex(i.Summary, "("); start >= 0 && strings.HasSuffix(i.Summary, ")") {
+		return calendar.getRegionCodes(i.Summary[start+1 : len(i.Summary)-1])
+	}
+
+	return nil
 }
 
-// getWeekends returns a list of dates that fall on Saturdays and Sundays
-func getWeekends(startDate, endDate string) ([]time.Time, error) {
-	var weekends []time.Time
-	start, err := time.Parse(DefaultTimeFormat, startDate)
-	if err != nil {
-		return weekends, err
+// getTime returns the date or timestamp of an event time, in its time zone if it is a timestamp
+func (e *EventTime) getTime(calendarTimeZone string) (time.Time, error) {
+	if e.DateTime == "" {
+		return time.Parse(DefaultTimeFormat, e.Date)
 	}
 
-	end, err := time.Parse(DefaultTimeFormat, endDate)
+	t, err := time.Parse(time.RFC3339, e.DateTime)
 	if err != nil {
-		return weekends, err
+		return t, err
 	}
 
-	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		if d.Weekday().String() == "Saturday" || d.Weekday().String() == "Sunday" {
-			weekends = append(weekends, d)
+	timeZone := e.TimeZone
+	if timeZone == "" {
+		timeZone = calendarTimeZone
+	}
+
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return t, err
 		}
+		t = t.In(loc)
 	}
 
-	return weekends, nil
+	return t, nil
 }
 
-// getVacationsWithoutLeaves returns free time of 3 (default) or more days where filing a vacation leave
-// is not needed (i.e. long weekends)
-func getVacationsWithoutLeaves(freeTime []time.Time) []*Vacation {
-	var toDate time.Time
-	days := 0
-	fromDate := freeTime[0]
-	var dates []*Vacation
-	i := 0
-
-	for i < len(freeTime) {
-		if days == 0 {
-			fromDate = freeTime[i]
-		}
+// toDate returns the calendar date of a time at midnight UTC
+func toDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
 
-		days += 1
-		if i == len(freeTime)-1 || freeTime[i].AddDate(0, 0, 1) != freeTime[i+1] {
-			toDate = freeTime[i]
-			if days >= defaultMinDaysWithoutLeave {
-				date := &Vacation{
-					Start: fromDate,
-					End:   toDate,
-					Count: days,
-				}
-				dates = append(dates, date)
-			}
-			days = 0
-		}
-		i += 1
+// classifyHoliday returns the kind of holiday based on the event description.
+// Events without a description (e.g. company calendars) are treated as public holidays.
+func classifyHoliday(description string) planner.HolidayKind {
+	switch {
+	case description == "", strings.HasPrefix(description, "Public holiday"):
+		return planner.KindPublic
+	case strings.HasPrefix(description, "Observance"):
+		return planner.KindObservance
+	default:
+		return planner.KindOther
 	}
-
-	return dates
 }
 
-// getSuggestions returns a list of suggested vacation dates
-func getSuggestions(pairs []*Vacation) []*Suggestion {
-	var suggestions []*Suggestion
-	for i, d := range pairs {
-		if i >= len(pairs)-1 {
-			continue
-		}
-
-		start := d.Start
-		end := d.End
-		nextStart := pairs[i+1].Start
-		nextEnd := pairs[i+1].End
-		leaves := int((nextStart.Sub(end).Hours() / 24) - 1)
-		if leaves <= 5 {
-			vacation := int(nextEnd.Sub(start).Hours() / 24)
-			if vacation-leaves > 1 {
-				suggestions = append(suggestions,
-					&Suggestion{
-						Vacation: vacation + 1,
-						Leaves:   leaves,
-						Start:    d.Start,
-						End:      pairs[i+1].End,
-					})
-			}
-		}
+// queryCalendarAPI gets the list of holidays from start to end (inclusive) from every page of the Calendar API
+func queryCalendarAPI(ctx context.Context, key, calendarID, start, end string) (*Events, error) {
+	endDate, err := time.Parse(DefaultTimeFormat, end)
+	if err != nil {
+		return nil, err
 	}
 
-	return suggestions
+	// recurring events (e.g. of a company calendar) are expanded into an event per occurrence
+	timeMax := endDate.AddDate(0, 0, 1).Format(DefaultTimeFormat)
+	query := fmt.Sprintf("key=%s&timeMin=%sT00:00:00Z&timeMax=%sT00:00:00Z&singleEvents=true", key, start, timeMax)
+
+	return queryEvents(ctx, calendarID, query)
 }
 
-// formatFreeTime returns a sorted list of holidays and weekends combined
-func formatFreeTime(holidays, weekends []time.Time) []time.Time {
-	var freeTime []time.Time
-	freeTime = append(freeTime, holidays...)
-	freeTime = append(freeTime, weekends...)
+// queryEvents gets the events matching a query from every page of the Calendar API
+func queryEvents(ctx context.Context, calendarID, query string) (*Events, error) {
+	url := fmt.Sprintf(eventsListURL+query, url.QueryEscape(calendarID))
+
+	var events *Events
+	pageToken := ""
+	for {
+		page, err := queryEventsPage(ctx, calendarID, url, pageToken)
+		if err != nil {
+			return nil, err
+		}
 
-	sort.Slice(freeTime, func(i, j int) bool {
-		return freeTime[i].Before(freeTime[j])
-	})
+		if events == nil {
+			events = page
+		} else {
+			events.Items = append(events.Items, page.Items...)
+			events.NextSyncToken = page.NextSyncToken
+		}
 
-	var newList []time.Time
-	for k, v := range freeTime {
-		if k == len(freeTime)-1 || v != freeTime[k+1] {
-			newList = append(newList, v)
+		if page.NextPageToken == "" {
+			break
 		}
+		pageToken = page.NextPageToken
 	}
+	events.NextPageToken = ""
 
-	return newList
+	return events, nil
 }
 
-// queryCalendarAPI gets the list of holidays from the Calendar API and writes it into a JSON file
-func queryCalendarAPI(events *Events, key, calendarID, start, end, filePath string) (*Events, error) {
-	id := url.QueryEscape(calendarID)
-	query := fmt.Sprintf("key=%s&timeMin=%sT00:00:00Z&timeMax=%sT00:00:00Z", key, start, end)
-	url := fmt.Sprintf(eventsListURL+query, id)
+// queryEventsPage gets a single page of events of a calendar from the Calendar API
+func queryEventsPage(ctx context.Context, calendarID, eventsURL, pageToken string) (*Events, error) {
+	if pageToken != "" {
+		eventsURL += "&pageToken=" + url.QueryEscape(pageToken)
+	}
 
-	f, err := os.Create(filePath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, eventsURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
 
-	resp, err := http.Get(url)
+	resp, err := HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unsuccessful - status code: %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusGone {
+		return nil, ErrSyncTokenExpired
 	}
 
-	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &events); err != nil {
-		return nil, err
-	}
-
-	s, err := json.MarshalIndent(events, "", "    ")
-	if err != nil {
-		return nil, err
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(calendarID, resp.StatusCode, body)
 	}
 
-	if _, err := f.Write(s); err != nil {
+	page := &Events{}
+	if err := json.Unmarshal(body, page); err != nil {
 		return nil, err
 	}
 
-	return events, nil
+	return page, nil
 }
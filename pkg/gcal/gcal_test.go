@@ -1,16 +1,29 @@
 package gcal
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/jvmistica/holiday-planner-go/pkg/rules"
+	"github.com/jvmistica/holiday-planner-go/pkg/transport"
 	"github.com/stretchr/testify/assert"
 )
 
+// withObservances returns the default planner options with the given observances opted in
+func withObservances(observances ...string) *planner.Options {
+	opts := planner.DefaultOptions()
+	opts.Observances = observances
+	return opts
+}
+
 func TestGetHolidays(t *testing.T) {
 	t.Run("error parsing date", func(t *testing.T) {
 		events := `{"summary": "Holidays in Austria",
@@ -30,7 +43,7 @@ func TestGetHolidays(t *testing.T) {
 		err := json.Unmarshal([]byte(events), &e)
 		assert.Nil(t, err)
 
-		holidays, err := getHolidays(e)
+		holidays, err := getHolidays(e, nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, holidays)
 	})
@@ -53,138 +66,230 @@ func TestGetHolidays(t *testing.T) {
 		err := json.Unmarshal([]byte(events), &e)
 		assert.Nil(t, err)
 
-		holidays, err := getHolidays(e)
+		holidays, err := getHolidays(e, nil)
 		assert.Nil(t, err)
 		assert.Equal(t, 3, len(holidays))
+		assert.Equal(t, planner.KindPublic, holidays[0].Kind)
 	})
-}
 
-func TestGetWeekends(t *testing.T) {
-	tests := []struct {
-		startDate     string
-		endDate       string
-		expectedCount int
-		wantErr       bool
-	}{
-		{
-			startDate:     "2023-05-01",
-			endDate:       "2023-05-31",
-			expectedCount: 8,
-		},
-		{
-			startDate:     "2023-12-15",
-			endDate:       "2024-01-15",
-			expectedCount: 10,
-		},
-		{
-			startDate: "2023/12/15",
-			endDate:   "2024-01-15",
-			wantErr:   true,
-		},
-		{
-			startDate: "2023-12-15",
-			endDate:   "2024/01/15",
-			wantErr:   true,
-		},
-	}
+	t.Run("multi-day and timed events", func(t *testing.T) {
+		events := `{"summary": "Company holidays",
+		 "timeZone": "Europe/Vienna",
+		 "items": [
+		     {
+		         "summary": "Christmas closure",
+		         "start": {"date": "2023-12-27"},
+		         "end": {"date": "2023-12-30"}
+		     },
+		     {
+		         "summary": "Single day",
+		         "start": {"date": "2023-12-22"},
+		         "end": {"date": "2023-12-23"}
+		     },
+		     {
+		         "summary": "Team event",
+		         "start": {"dateTime": "2023-12-20T23:30:00Z"},
+		         "end": {"dateTime": "2023-12-21T05:00:00Z"}
+		     },
+		     {
+		         "summary": "Overnight in New York",
+		         "start": {"dateTime": "2023-12-18T20:00:00-05:00", "timeZone": "America/New_York"},
+		         "end": {"dateTime": "2023-12-19T00:00:00-05:00", "timeZone": "America/New_York"}
+		     }
+		 ]}`
 
-	for _, tt := range tests {
-		weekends, err := getWeekends(tt.startDate, tt.endDate)
-		if tt.wantErr {
-			assert.Nil(t, weekends)
-			assert.NotNil(t, err)
-		} else {
-			assert.Nil(t, err)
-			assert.Equal(t, tt.expectedCount, len(weekends))
+		var e *Events
+		err := json.Unmarshal([]byte(events), &e)
+		assert.Nil(t, err)
+
+		holidays, err := getHolidays(e, nil)
+		assert.Nil(t, err)
+
+		var dates []string
+		for _, h := range holidays {
+			dates = append(dates, h.Date.Format(DefaultTimeFormat))
 		}
-	}
-}
+		assert.Equal(t, []string{"2023-12-27", "2023-12-28", "2023-12-29", "2023-12-22", "2023-12-21", "2023-12-18"}, dates)
+		assert.Equal(t, "Christmas closure", holidays[2].Name)
+	})
 
-func TestGetVacationsWithoutLeaves(t *testing.T) {
-	dates := `["2023-05-15T00:00:00Z", "2023-05-27T00:00:00Z", "2023-05-28T00:00:00Z", "2023-05-29T00:00:00Z"]`
+	t.Run("error parsing end date", func(t *testing.T) {
+		events := `{"items": [{"start": {"date": "2023-12-27"}, "end": {"date": "2023/12/30"}}]}`
 
-	var free []time.Time
-	err := json.Unmarshal([]byte(dates), &free)
-	assert.Nil(t, err)
+		var e *Events
+		err := json.Unmarshal([]byte(events), &e)
+		assert.Nil(t, err)
 
-	result := getVacationsWithoutLeaves(free)
-	assert.Equal(t, 1, len(result))
-	assert.Equal(t, 3, result[0].Count)
-}
+		holidays, err := getHolidays(e, nil)
+		assert.NotNil(t, err)
+		assert.Nil(t, holidays)
+	})
 
-func TestGetSuggestions(t *testing.T) {
-	t.Run("one pair", func(t *testing.T) {
-		dates := `[{"start": "2023-05-24T00:00:00Z", "end": "2023-05-28T00:00:00Z"}]`
+	t.Run("unknown time zone", func(t *testing.T) {
+		events := `{"items": [{"start": {"dateTime": "2023-12-20T10:00:00Z", "timeZone": "Nowhere/Nothing"}}]}`
 
-		var free []*Vacation
-		err := json.Unmarshal([]byte(dates), &free)
+		var e *Events
+		err := json.Unmarshal([]byte(events), &e)
 		assert.Nil(t, err)
 
-		result := getSuggestions(free)
-		assert.Nil(t, result)
+		holidays, err := getHolidays(e, nil)
+		assert.NotNil(t, err)
+		assert.Nil(t, holidays)
 	})
 
-	t.Run("two pairs", func(t *testing.T) {
-		dates := `[{"start": "2023-12-23T00:00:00Z", "end": "2023-12-26T00:00:00Z"}, {"start": "2023-12-30T00:00:00Z", "end": "2024-01-01T00:00:00Z"}]`
+	t.Run("regional holidays", func(t *testing.T) {
+		events := `{"items": [
+			{"summary": "Epiphany", "description": "Public holiday in Baden-Württemberg, Bavaria and Saxony-Anhalt", "start": {"date": "2024-01-06"}},
+			{"summary": "Carnival (Cologne)", "description": "Observance", "start": {"date": "2024-02-12"}},
+			{"summary": "Corpus Christi (Bavaria & Hesse)", "description": "Common local holiday", "start": {"date": "2024-05-30"}},
+			{"summary": "Reformation Day (regional holiday)", "description": "Public holiday", "start": {"date": "2024-10-31"}},
+			{"summary": "Day of German Unity", "description": "Public holiday\nHolidays in Germany", "start": {"date": "2024-10-03"}}]}`
+
+		var e *Events
+		err := json.Unmarshal([]byte(events), &e)
+		assert.Nil(t, err)
 
-		var free []*Vacation
-		err := json.Unmarshal([]byte(dates), &free)
+		holidays, err := getHolidays(e, findHolidayCalendarByID("de.german#holiday@group.v.calendar.google.com"))
 		assert.Nil(t, err)
 
-		result := getSuggestions(free)
-		assert.Equal(t, 1, len(result))
-		assert.Equal(t, 10, result[0].Vacation)
-		assert.Equal(t, 3, result[0].Leaves)
-		assert.Equal(t, "2023-12-23", result[0].Start.Format(DefaultTimeFormat))
-		assert.Equal(t, "2024-01-01", result[0].End.Format(DefaultTimeFormat))
+		var regions []string
+		for _, h := range holidays {
+			regions = append(regions, h.Name+" "+h.Region)
+		}
+		assert.Equal(t, []string{
+			"Epiphany DE-BW", "Epiphany DE-BY", "Epiphany DE-ST",
+			"Carnival (Cologne) ",
+			"Corpus Christi (Bavaria & Hesse) DE-BY", "Corpus Christi (Bavaria & Hesse) DE-HE",
+			"Reformation Day (regional holiday) ",
+			"Day of German Unity ",
+		}, regions)
+
+		// regions are unknown for other calendars
+		holidays, err = getHolidays(e, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, len(holidays))
+		assert.Equal(t, "", holidays[0].Region)
 	})
 
-	t.Run("three pairs", func(t *testing.T) {
-		dates := `[{"start": "2023-05-22T00:00:00Z", "end": "2023-05-23T00:00:00Z"}, {"start": "2023-05-24T00:00:00Z", "end": "2023-05-25T00:00:00Z"}, {"start": "2023-05-27T00:00:00Z", "end": "2023-05-28T00:00:00Z"}]`
+	t.Run("provider region", func(t *testing.T) {
+		origDir, origTTL := CacheDir, CacheTTL
+		CacheDir, CacheTTL = t.TempDir(), 0
+		defer func() {
+			CacheDir, CacheTTL = origDir, origTTL
+		}()
+
+		calendarID := "en.austrian#holiday@group.v.calendar.google.com"
+		cached := `{"calendarId": "en.austrian#holiday@group.v.calendar.google.com",
+			"ranges": [{"start": "2024-11-01", "end": "2024-11-30", "fetchedAt": "2024-01-01T00:00:00Z"}],
+			"events": {"items": [
+				{"summary": "All Saints' Day", "description": "Public holiday", "start": {"date": "2024-11-01"}},
+				{"summary": "St. Leopold's Day", "description": "Public holiday in Lower Austria, Vienna", "start": {"date": "2024-11-15"}}]}}`
+		err := os.WriteFile(getCacheFilePath(CacheDir, calendarID), []byte(cached), 0o600)
+		assert.Nil(t, err)
+
+		start, end := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+		holidays, err := (&Provider{Key: "abc", CalendarID: calendarID, Region: "AT-9"}).GetHolidays(start, end)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(holidays))
+		assert.Equal(t, "AT-9", holidays[1].Region)
+
+		holidays, err = (&Provider{Key: "abc", CalendarID: calendarID, Region: "AT-5"}).GetHolidays(start, end)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(holidays))
+		assert.Equal(t, "All Saints' Day", holidays[0].Name)
 
-		var free []*Vacation
-		err := json.Unmarshal([]byte(dates), &free)
+		// without a region, only nationwide holidays are days off
+		holidays, err = NewProvider("abc", calendarID).GetHolidays(start, end)
 		assert.Nil(t, err)
+		assert.Equal(t, 1, len(holidays))
+		assert.Equal(t, "All Saints' Day", holidays[0].Name)
+
+		// the built-in rules and override files give the same holidays for a country or region
+		for _, region := range []string{"", "AT-9"} {
+			code := "AT"
+			if region != "" {
+				code = region
+			}
+
+			rulesProvider, err := rules.NewProvider(code)
+			assert.Nil(t, err)
+
+			nationwide, err := rules.NewProvider("AT")
+			assert.Nil(t, err)
+
+			providers := map[string]planner.HolidayProvider{
+				"google": &Provider{Key: "abc", CalendarID: calendarID, Region: region},
+				"rules":  rulesProvider,
+				"overrides": &planner.OverrideProvider{
+					Provider:  nationwide,
+					Overrides: []*planner.Override{{Date: "11-15", Name: "St. Leopold's Day", Regions: []string{"AT-3", "AT-9"}}},
+					Region:    region,
+				},
+			}
 
-		result := getSuggestions(free)
-		assert.Equal(t, 2, len(result))
-		assert.Equal(t, 4, result[0].Vacation)
-		assert.Equal(t, 0, result[0].Leaves)
-		assert.Equal(t, "2023-05-22", result[0].Start.Format(DefaultTimeFormat))
-		assert.Equal(t, "2023-05-25", result[0].End.Format(DefaultTimeFormat))
-		assert.Equal(t, 5, result[1].Vacation)
-		assert.Equal(t, 1, result[1].Leaves)
-		assert.Equal(t, "2023-05-24", result[1].Start.Format(DefaultTimeFormat))
-		assert.Equal(t, "2023-05-28", result[1].End.Format(DefaultTimeFormat))
+			for name, provider := range providers {
+				holidays, err := provider.GetHolidays(start, end)
+				assert.Nil(t, err)
+
+				var days []string
+				for _, h := range holidays {
+					if h.Kind == planner.KindPublic {
+						days = append(days, h.Date.Format(DefaultTimeFormat)+" "+h.Name)
+					}
+				}
+
+				expected := []string{"2024-11-01 All Saints' Day"}
+				if region != "" {
+					expected = append(expected, "2024-11-15 St. Leopold's Day")
+				}
+				assert.Equal(t, expected, days, name+" "+region)
+			}
+		}
 	})
-}
 
-func TestFormatFreeTime(t *testing.T) {
-	holidays := `["2023-12-25T00:00:00Z", "2023-12-26T00:00:00Z", "2023-12-21T00:00:00Z", "2024-01-01T00:00:00Z"]`
-	weekends := `["2023-12-23T00:00:00Z", "2023-12-24T00:00:00Z", "2023-12-30T00:00:00Z", "2023-12-31T00:00:00Z"]`
+	t.Run("cached Austrian calendar", func(t *testing.T) {
+		data, err := os.ReadFile("data/en.austrian#holiday@group.v.calendar.google.com.json")
+		assert.Nil(t, err)
 
-	var h []time.Time
-	err := json.Unmarshal([]byte(holidays), &h)
-	assert.Nil(t, err)
+		var e *Events
+		err = json.Unmarshal(data, &e)
+		assert.Nil(t, err)
 
-	var w []time.Time
-	err = json.Unmarshal([]byte(weekends), &w)
-	assert.Nil(t, err)
+		holidays, err := getHolidays(e, findHolidayCalendarByID("en.austrian#holiday@group.v.calendar.google.com"))
+		assert.Nil(t, err)
+		assert.Equal(t, 16, len(holidays))
 
-	result := formatFreeTime(h, w)
-	assert.Equal(t, 8, len(result))
-	assert.Equal(t, "2023-12-21", result[0].Format(DefaultTimeFormat))
-	assert.Equal(t, "2024-01-01", result[7].Format(DefaultTimeFormat))
+		kinds := map[planner.HolidayKind][]string{}
+		for _, h := range holidays {
+			kinds[h.Kind] = append(kinds[h.Kind], h.Name)
+		}
+		assert.Equal(t, 9, len(kinds[planner.KindPublic]))
+		assert.Equal(t, 7, len(kinds[planner.KindObservance]))
+		assert.Contains(t, kinds[planner.KindPublic], "Corpus Christi")
+		assert.Contains(t, kinds[planner.KindObservance], "Yom Kippur")
+		assert.Contains(t, kinds[planner.KindObservance], "Father's Day")
+		assert.Contains(t, kinds[planner.KindObservance], "Daylight Saving Time ends")
+		assert.Contains(t, kinds[planner.KindObservance], "All Souls' Day")
+	})
 }
 
-func TestQueryCalendarAPI(t *testing.T) {
-	t.Run("failed to create JSON file", func(t *testing.T) {
-		var events *Events
-		events, err := queryCalendarAPI(events, "def", "test", "2023-08-01T00:00:00Z", "2023-09-30T00:00:00Z", "/not/exist")
-		assert.NotNil(t, err)
-		assert.Nil(t, events)
-	})
+func TestClassifyHoliday(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    planner.HolidayKind
+	}{
+		{description: "Public holiday", expected: planner.KindPublic},
+		{description: "", expected: planner.KindPublic},
+		{description: "Observance\nTo hide observances, go to Google Calendar Settings > Holidays in Austria", expected: planner.KindObservance},
+		{description: "Season", expected: planner.KindOther},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, classifyHoliday(tt.description))
+	}
+}
 
+func TestQueryCalendarAPI(t *testing.T) {
 	t.Run("error querying Google calendar", func(t *testing.T) {
 		origURL := eventsListURL
 		eventsListURL = "/not/exist"
@@ -192,8 +297,7 @@ func TestQueryCalendarAPI(t *testing.T) {
 			eventsListURL = origURL
 		}()
 
-		var events *Events
-		events, err := queryCalendarAPI(events, "def", "test", "2023-08-01T00:00:00Z", "2023-09-30T00:00:00Z", t.TempDir()+"test.json")
+		events, err := queryCalendarAPI(context.Background(), "def", "test", "2023-08-01", "2023-09-30")
 		assert.NotNil(t, err)
 		assert.Nil(t, events)
 	})
@@ -204,16 +308,15 @@ func TestQueryCalendarAPI(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		ts.URL = ts.URL + "/%s?" + "key=abc&timeMin=2023-08-01T00:00:00Z&timeMax=2023-09-30T00:00:00Z"
+		ts.URL = ts.URL + "/%s?"
 		origURL := eventsListURL
 		eventsListURL = ts.URL
 		defer func() {
 			eventsListURL = origURL
 		}()
 
-		var events *Events
-		events, err := queryCalendarAPI(events, "def", "test", "2023-08-01T00:00:00Z", "2023-09-30T00:00:00Z", t.TempDir()+"test.json")
-		assert.Equal(t, "unsuccessful - status code: 401", err.Error())
+		events, err := queryCalendarAPI(context.Background(), "def", "test", "2023-08-01", "2023-09-30")
+		assert.Equal(t, "failed to get events of calendar test - status code: 401", err.Error())
 		assert.Nil(t, events)
 	})
 
@@ -225,15 +328,14 @@ func TestQueryCalendarAPI(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		ts.URL = ts.URL + "/%s?" + "key=abc&timeMin=2023-08-01T00:00:00Z&timeMax=2023-09-30T00:00:00Z"
+		ts.URL = ts.URL + "/%s?"
 		origURL := eventsListURL
 		eventsListURL = ts.URL
 		defer func() {
 			eventsListURL = origURL
 		}()
 
-		var events *Events
-		events, err := queryCalendarAPI(events, "abc", "test", "2023-08-01T00:00:00Z", "2023-09-30T00:00:00Z", t.TempDir()+"test.json")
+		events, err := queryCalendarAPI(context.Background(), "abc", "test", "2023-08-01", "2023-09-30")
 		assert.NotNil(t, err)
 		assert.Nil(t, events)
 	})
@@ -262,29 +364,115 @@ func TestQueryCalendarAPI(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		ts.URL = ts.URL + "/%s?" + "key=abc&timeMin=2023-08-01T00:00:00Z&timeMax=2023-09-30T00:00:00Z"
+		ts.URL = ts.URL + "/%s?"
 		origURL := eventsListURL
 		eventsListURL = ts.URL
 		defer func() {
 			eventsListURL = origURL
 		}()
 
-		var events *Events
-		events, err := queryCalendarAPI(events, "abc", "test", "2023-08-01T00:00:00Z", "2023-09-30T00:00:00Z", t.TempDir()+"test.json")
+		events, err := queryCalendarAPI(context.Background(), "abc", "test", "2023-08-01", "2023-09-30")
 		assert.Nil(t, err)
 		assert.Equal(t, "Holidays in Austria", events.Summary)
 		assert.Equal(t, "Assumption of Mary", events.Items[0].Summary)
 		assert.Equal(t, "Yom Kippur", events.Items[1].Summary)
 	})
+
+	t.Run("paginated response", func(t *testing.T) {
+		var pageTokens []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pageToken := r.URL.Query().Get("pageToken")
+			pageTokens = append(pageTokens, pageToken)
+
+			w.WriteHeader(http.StatusOK)
+			var err error
+			switch pageToken {
+			case "":
+				_, err = w.Write([]byte(`{
+					"summary": "Holidays in Austria",
+					"nextPageToken": "page2",
+					"items": [{"summary": "Assumption of Mary", "description": "Public holiday", "start": {"date": "2023-08-15"}}]}`))
+			case "page2":
+				_, err = w.Write([]byte(`{
+					"summary": "Holidays in Austria",
+					"nextPageToken": "page3",
+					"items": [{"summary": "National Day", "description": "Public holiday", "start": {"date": "2023-10-26"}}]}`))
+			default:
+				_, err = w.Write([]byte(`{
+					"summary": "Holidays in Austria",
+					"nextSyncToken": "CMDu0emHs_8CEAAYASCn_tSAAg==",
+					"items": [{"summary": "All Saints' Day", "description": "Public holiday", "start": {"date": "2023-11-01"}}]}`))
+			}
+			assert.Nil(t, err)
+		}))
+		defer ts.Close()
+
+		origURL := eventsListURL
+		eventsListURL = ts.URL + "/%s?"
+		defer func() {
+			eventsListURL = origURL
+		}()
+
+		events, err := queryCalendarAPI(context.Background(), "abc", "test", "2023-08-01", "2023-11-30")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"", "page2", "page3"}, pageTokens)
+		assert.Equal(t, 3, len(events.Items))
+		assert.Equal(t, "All Saints' Day", events.Items[2].Summary)
+		assert.Equal(t, "CMDu0emHs_8CEAAYASCn_tSAAg==", events.NextSyncToken)
+		assert.Equal(t, "", events.NextPageToken)
+	})
+
+	t.Run("error on a later page", func(t *testing.T) {
+		attempts := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("pageToken") != "" {
+				attempts++
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"nextPageToken": "page2", "items": [{"summary": "Assumption of Mary", "start": {"date": "2023-08-15"}}]}`))
+			assert.Nil(t, err)
+		}))
+		defer ts.Close()
+
+		origURL := eventsListURL
+		eventsListURL = ts.URL + "/%s?"
+		defer func() {
+			eventsListURL = origURL
+		}()
+
+		origClient := HTTPClient
+		HTTPClient = transport.NewClient(transport.Config{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+		defer func() {
+			HTTPClient = origClient
+		}()
+
+		events, err := queryCalendarAPI(context.Background(), "abc", "test", "2023-08-01", "2023-11-30")
+		assert.Equal(t, "failed to get events of calendar test - status code: 500", err.Error())
+		assert.Nil(t, events)
+		assert.Equal(t, 3, attempts)
+	})
 }
 
 func TestGetCalendarEvents(t *testing.T) {
+	t.Run("invalid options", func(t *testing.T) {
+		opts := planner.DefaultOptions()
+		opts.MaxLeaves = -1
+
+		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test", opts)
+		assert.Equal(t, "invalid maximum leaves: -1", err.Error())
+		assert.Nil(t, v)
+		assert.Nil(t, s)
+	})
+
 	t.Run("file does not exist", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := DefaultFilePath
-		DefaultFilePath = tmpDir + "%s"
+		origDir := CacheDir
+		CacheDir = tmpDir
 		defer func() {
-			DefaultFilePath = origDir
+			CacheDir = origDir
 		}()
 
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -317,28 +505,28 @@ func TestGetCalendarEvents(t *testing.T) {
 			eventsListURL = origURL
 		}()
 
-		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test")
+		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test", withObservances("Yom Kippur"))
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(v))
 		assert.Equal(t, 3, v[0].Count)
 		assert.Equal(t, "2023-09-23", v[0].Start.Format(DefaultTimeFormat))
 		assert.Equal(t, "2023-09-25", v[0].End.Format(DefaultTimeFormat))
-		assert.Nil(t, s)
+		assert.Equal(t, 1, len(s))
+		assert.Equal(t, 1, s[0].Leaves)
+		assert.Equal(t, "2023-08-12", s[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-08-15", s[0].End.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-08-14", s[0].LeaveDates[0].Format(DefaultTimeFormat))
 	})
 
 	t.Run("file exists", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := DefaultFilePath
-		DefaultFilePath = tmpDir + "%s"
+		origDir := CacheDir
+		CacheDir = tmpDir
 		defer func() {
-			DefaultFilePath = origDir
+			CacheDir = origDir
 		}()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
-
-		_, err = f.Write([]byte(`{
+		writeCache(t, tmpDir, `{
 			"summary": "Holidays in Austria",
 			"nextSyncToken": "CMDu0emHs_8CEAAYASCn_tSAAg==",
 			"items": [{
@@ -354,31 +542,35 @@ func TestGetCalendarEvents(t *testing.T) {
 				"start": {
 					"date": "2023-09-25"
 				}
-			}]}`))
+			}]}`)
+
+		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test", nil)
 		assert.Nil(t, err)
+		assert.Nil(t, v)
+		assert.Equal(t, 1, len(s))
 
-		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test")
+		v, s, err = GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test", withObservances("Yom Kippur"))
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(v))
 		assert.Equal(t, 3, v[0].Count)
 		assert.Equal(t, "2023-09-23", v[0].Start.Format(DefaultTimeFormat))
 		assert.Equal(t, "2023-09-25", v[0].End.Format(DefaultTimeFormat))
-		assert.Nil(t, s)
+		assert.Equal(t, 1, len(s))
+		assert.Equal(t, 1, s[0].Leaves)
+		assert.Equal(t, "2023-08-12", s[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-08-15", s[0].End.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-08-14", s[0].LeaveDates[0].Format(DefaultTimeFormat))
 	})
 
 	t.Run("error unmarshalling JSON file", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := DefaultFilePath
-		DefaultFilePath = tmpDir + "%s"
+		origDir := CacheDir
+		CacheDir = tmpDir
 		defer func() {
-			DefaultFilePath = origDir
+			CacheDir = origDir
 		}()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
-
-		_, err = f.Write([]byte(`{
+		writeCache(t, tmpDir, `{
 			"summary": "Holidays in Austria",
 			"nextSyncToken": "CMDu0emHs_8CEAAYASCn_tSAAg==",
 			"items": [[{
@@ -394,10 +586,9 @@ func TestGetCalendarEvents(t *testing.T) {
 				"start": {
 					"date": "2023-09-25"
 				}
-			}]]}`))
-		assert.Nil(t, err)
+			}]]}`)
 
-		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test")
+		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test", nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, v)
 		assert.Nil(t, s)
@@ -405,34 +596,35 @@ func TestGetCalendarEvents(t *testing.T) {
 
 	t.Run("error parsing JSON file", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := DefaultFilePath
-		DefaultFilePath = tmpDir + "%s"
+		origDir := CacheDir
+		CacheDir = tmpDir
 		defer func() {
-			DefaultFilePath = origDir
+			CacheDir = origDir
 		}()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
-
-		_, err = f.Write([]byte(`invalid`))
-		assert.Nil(t, err)
+		writeCache(t, tmpDir, `invalid`)
 
-		v, s, err := GetCalendarEvents("abc", "2023-08-01T00:00:00Z", "2023-09-30T00:00:00Z", "test")
+		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test", nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, v)
 		assert.Nil(t, s)
 	})
 
 	t.Run("error querying calendar API", func(t *testing.T) {
-		tmpDir := "/not/exist"
-		origDir := DefaultFilePath
-		DefaultFilePath = tmpDir + "%s"
+		tmpDir := t.TempDir()
+		origDir := CacheDir
+		CacheDir = tmpDir
+		defer func() {
+			CacheDir = origDir
+		}()
+
+		origURL := eventsListURL
+		eventsListURL = "/not/exist"
 		defer func() {
-			DefaultFilePath = origDir
+			eventsListURL = origURL
 		}()
 
-		v, s, err := GetCalendarEvents("abc", "2023-08-01T00:00:00Z", "2023-09-30T00:00:00Z", "test")
+		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test", nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, v)
 		assert.Nil(t, s)
@@ -440,17 +632,13 @@ func TestGetCalendarEvents(t *testing.T) {
 
 	t.Run("error parsing date while getting holidays", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := DefaultFilePath
-		DefaultFilePath = tmpDir + "%s"
+		origDir := CacheDir
+		CacheDir = tmpDir
 		defer func() {
-			DefaultFilePath = origDir
+			CacheDir = origDir
 		}()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
-
-		_, err = f.Write([]byte(`{
+		writeCache(t, tmpDir, `{
 			"summary": "Holidays in Austria",
 			"nextSyncToken": "CMDu0emHs_8CEAAYASCn_tSAAg==",
 			"items": [{
@@ -466,28 +654,23 @@ func TestGetCalendarEvents(t *testing.T) {
 				"start": {
 					"date": "2023/09/25"
 				}
-			}]}`))
-		assert.Nil(t, err)
+			}]}`)
 
-		v, s, err := GetCalendarEvents("abc", "2023-08-01T00:00:00Z", "2023-09-30T00:00:00Z", "test")
+		v, s, err := GetCalendarEvents("abc", "2023-08-01", "2023-09-30", "test", nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, v)
 		assert.Nil(t, s)
 	})
 
-	t.Run("error parsing date while getting weekends", func(t *testing.T) {
+	t.Run("error parsing start and end dates", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := DefaultFilePath
-		DefaultFilePath = tmpDir + "%s"
+		origDir := CacheDir
+		CacheDir = tmpDir
 		defer func() {
-			DefaultFilePath = origDir
+			CacheDir = origDir
 		}()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
-
-		_, err = f.Write([]byte(`{
+		writeCache(t, tmpDir, `{
 			"summary": "Holidays in Austria",
 			"nextSyncToken": "CMDu0emHs_8CEAAYASCn_tSAAg==",
 			"items": [{
@@ -503,12 +686,24 @@ func TestGetCalendarEvents(t *testing.T) {
 				"start": {
 					"date": "2023-09-25"
 				}
-			}]}`))
-		assert.Nil(t, err)
+			}]}`)
+
+		v, s, err := GetCalendarEvents("abc", "2023/08/01", "2023-09-30", "test", nil)
+		assert.NotNil(t, err)
+		assert.Nil(t, v)
+		assert.Nil(t, s)
 
-		v, s, err := GetCalendarEvents("abc", "2023/08/01T00:00:00Z", "2023/09/30T00:00:00Z", "test")
+		v, s, err = GetCalendarEvents("abc", "2023-08-01", "2023/09/30", "test", nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, v)
 		assert.Nil(t, s)
 	})
 }
+
+// writeCache writes events into the cache of the "test" calendar as fetched from 2023-08-01 to 2023-09-30
+func writeCache(t *testing.T, dir, events string) {
+	entry := fmt.Sprintf(`{"calendarId": "test", "ranges": [{"start": "2023-08-01", "end": "2023-09-30", "fetchedAt": %q}], "events": %s}`,
+		time.Now().UTC().Format(time.RFC3339), events)
+	err := os.WriteFile(filepath.Join(dir, "test.json"), []byte(entry), 0o600)
+	assert.Nil(t, err)
+}
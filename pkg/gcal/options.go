@@ -0,0 +1,15 @@
+package gcal
+
+import "github.com/jvmistica/holiday-planner-go/pkg/planner"
+
+// PlannerOptions contains the settings used to compute free time and vacation suggestions.
+//
+// Deprecated: Use planner.Options instead.
+type PlannerOptions = planner.Options
+
+// DefaultPlannerOptions returns the default settings of the planner.
+//
+// Deprecated: Use planner.DefaultOptions instead.
+func DefaultPlannerOptions() *PlannerOptions {
+	return planner.DefaultOptions()
+}
@@ -0,0 +1,14 @@
+package gcal
+
+import (
+	"testing"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPlannerOptions(t *testing.T) {
+	var opts *PlannerOptions = DefaultPlannerOptions()
+	assert.Equal(t, planner.DefaultOptions(), opts)
+	assert.Nil(t, opts.Validate())
+}
@@ -0,0 +1,222 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+)
+
+var (
+	// ErrSyncTokenExpired is returned by the Calendar API (HTTP 410) when a sync token can no longer be used
+	ErrSyncTokenExpired = errors.New("sync token is no longer valid")
+
+	statusCancelled = "cancelled"
+)
+
+// ChangeType is the kind of change to a holiday between two syncs
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeUpdated ChangeType = "changed"
+	ChangeRemoved ChangeType = "removed"
+)
+
+// HolidayChange is a holiday that was added, changed or removed since the last sync
+type HolidayChange struct {
+	Type     ChangeType
+	Item     *Item
+	Previous *Item
+}
+
+// String returns a description of the change (e.g. "added: 2024-05-02 New Holiday")
+func (c *HolidayChange) String() string {
+	if c.Type == ChangeUpdated && (c.Previous.Summary != c.Item.Summary || getItemDate(c.Previous) != getItemDate(c.Item)) {
+		return fmt.Sprintf("%s: %s %s (was %s %s)", c.Type, getItemDate(c.Item), c.Item.Summary, getItemDate(c.Previous), c.Previous.Summary)
+	}
+
+	return fmt.Sprintf("%s: %s %s", c.Type, getItemDate(c.Item), c.Item.Summary)
+}
+
+// SyncReport contains the changes of a calendar since its last sync
+type SyncReport struct {
+	CalendarID string
+	FullResync bool
+	Changes    []*HolidayChange
+}
+
+// SyncCalendar updates a cached calendar with the events that changed since it was last fetched, using its sync token.
+// If the sync token has expired, every cached range is fetched again.
+func SyncCalendar(key, calendarID string) (*SyncReport, error) {
+	return SyncCalendarContext(context.Background(), key, calendarID)
+}
+
+// SyncCalendarContext is SyncCalendar with a context that cancels the requests to the Calendar API.
+// The cache is left unchanged if ctx is done before it is written.
+func SyncCalendarContext(ctx context.Context, key, calendarID string) (*SyncReport, error) {
+	dir, err := GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	filePath := getCacheFilePath(dir, calendarID)
+	entry, err := readCacheEntry(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{CalendarID: calendarID}
+	previous := entry.Events.Items
+
+	if entry.Events.NextSyncToken != "" {
+		log.Print("Initiating incremental sync..")
+
+		// a sync token can only be used with the singleEvents of the query it came from
+		query := fmt.Sprintf("key=%s&syncToken=%s&singleEvents=true", key, url.QueryEscape(entry.Events.NextSyncToken))
+		events, err := queryEvents(ctx, calendarID, query)
+		switch {
+		case err == nil:
+			entry.apply(events)
+		case errors.Is(err, ErrSyncTokenExpired):
+			report.FullResync = true
+		default:
+			return nil, err
+		}
+	} else {
+		report.FullResync = true
+	}
+
+	if report.FullResync {
+		log.Print("Initiating full sync..")
+
+		if err := entry.resync(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Changes = diffItems(previous, entry.Events.Items)
+	if err := writeCacheEntry(ctx, dir, filePath, entry); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// apply merges the added, changed and cancelled events of an incremental sync into the cache. The sync covers the
+// whole calendar, so events outside the cached ranges are left out, and events that moved out of them are removed.
+func (c *CacheEntry) apply(events *Events) {
+	items := map[string]*Item{}
+	var order []string
+	for _, i := range c.Events.Items {
+		key := getItemKey(i)
+		items[key] = i
+		order = append(order, key)
+	}
+
+	for _, i := range events.Items {
+		key := getItemKey(i)
+		if _, ok := items[key]; !ok {
+			order = append(order, key)
+		}
+
+		if i.Status == statusCancelled || !c.isCached(getItemDate(i)) {
+			delete(items, key)
+		} else {
+			items[key] = i
+		}
+	}
+
+	var merged []*Item
+	for _, key := range order {
+		if i, ok := items[key]; ok {
+			merged = append(merged, i)
+			delete(items, key)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return getItemDate(merged[i]) < getItemDate(merged[j])
+	})
+
+	c.Events.Items = merged
+	c.Events.NextSyncToken = events.NextSyncToken
+}
+
+// isCached checks if a date (YYYY-MM-DD) is in one of the cached ranges
+func (c *CacheEntry) isCached(date string) bool {
+	for _, r := range c.Ranges {
+		if date >= r.Start && date <= r.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resync fetches every cached range again and replaces the cached events
+func (c *CacheEntry) resync(ctx context.Context, key string) error {
+	ranges := c.Ranges
+	c.Ranges = nil
+	c.Events = &Events{}
+
+	for _, r := range ranges {
+		events, err := queryCalendarAPI(ctx, key, c.CalendarID, r.Start, r.End)
+		if err != nil {
+			return err
+		}
+		c.merge(r, events)
+	}
+
+	return nil
+}
+
+// diffItems returns the events that were added, changed or removed between two lists of events
+func diffItems(previous, current []*Item) []*HolidayChange {
+	var changes []*HolidayChange
+	old := map[string]*Item{}
+	for _, i := range previous {
+		old[getItemKey(i)] = i
+	}
+
+	for _, i := range current {
+		key := getItemKey(i)
+		p, ok := old[key]
+		delete(old, key)
+
+		switch {
+		case !ok:
+			changes = append(changes, &HolidayChange{Type: ChangeAdded, Item: i})
+		case !sameItem(p, i):
+			changes = append(changes, &HolidayChange{Type: ChangeUpdated, Item: i, Previous: p})
+		}
+	}
+
+	for _, i := range previous {
+		if _, ok := old[getItemKey(i)]; ok {
+			changes = append(changes, &HolidayChange{Type: ChangeRemoved, Item: i})
+		}
+	}
+
+	return changes
+}
+
+// getItemKey returns the ID of an event, or its date and summary if it has no ID
+func getItemKey(item *Item) string {
+	if item.ID != "" {
+		return item.ID
+	}
+
+	return getItemDate(item) + " " + item.Summary
+}
+
+// sameItem checks if two events have the same summary, description and dates
+func sameItem(a, b *Item) bool {
+	return a.Summary == b.Summary &&
+		a.Description == b.Description &&
+		a.Start == b.Start &&
+		(a.End == nil) == (b.End == nil) &&
+		(a.End == nil || *a.End == *b.End)
+}
@@ -0,0 +1,209 @@
+package gcal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncCalendar(t *testing.T) {
+	cached := `{
+		"calendarId": "test",
+		"ranges": [{"start": "2024-01-01", "end": "2024-12-31", "fetchedAt": "2024-01-01T00:00:00Z"}],
+		"events": {"nextSyncToken": "token1", "items": [
+			{"id": "a", "summary": "New Year's Day", "description": "Public holiday", "start": {"date": "2024-01-01"}},
+			{"id": "b", "summary": "Epiphany", "description": "Public holiday", "start": {"date": "2024-01-06"}},
+			{"id": "c", "summary": "Labour Day", "description": "Public holiday", "start": {"date": "2024-05-01"}}]}}`
+
+	var syncTokens []string
+	var status int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		syncTokens = append(syncTokens, r.URL.Query().Get("syncToken"))
+		assert.Equal(t, "true", r.URL.Query().Get("singleEvents"))
+		if r.URL.Query().Get("syncToken") != "" && status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("syncToken") != "" {
+			_, err := w.Write([]byte(`{"nextSyncToken": "token2", "items": [
+				{"id": "b", "summary": "Epiphany", "description": "Observance", "start": {"date": "2024-01-06"}},
+				{"id": "c", "status": "cancelled"},
+				{"id": "d", "summary": "Liberation Day", "description": "Public holiday", "start": {"date": "2024-05-05"}},
+				{"id": "f", "summary": "New Year's Day", "description": "Public holiday", "start": {"date": "2025-01-01"}}]}`))
+			assert.Nil(t, err)
+			return
+		}
+
+		_, err := w.Write([]byte(`{"nextSyncToken": "token3", "items": [
+			{"id": "a", "summary": "New Year's Day", "description": "Public holiday", "start": {"date": "2024-01-01"}},
+			{"id": "c", "summary": "Labour Day", "description": "Public holiday", "start": {"date": "2024-05-01"}},
+			{"id": "e", "summary": "Reformation Day", "description": "Public holiday", "start": {"date": "2024-10-31"}}]}`))
+		assert.Nil(t, err)
+	}))
+	defer ts.Close()
+
+	origURL := eventsListURL
+	eventsListURL = ts.URL + "/%s?"
+	defer func() {
+		eventsListURL = origURL
+	}()
+
+	t.Run("incremental sync", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = t.TempDir()
+		defer func() {
+			CacheDir = origDir
+		}()
+		syncTokens, status = nil, http.StatusOK
+
+		err := os.WriteFile(filepath.Join(CacheDir, "test.json"), []byte(cached), 0o600)
+		assert.Nil(t, err)
+
+		report, err := SyncCalendar("abc", "test")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"token1"}, syncTokens)
+		assert.False(t, report.FullResync)
+		assert.Equal(t, 3, len(report.Changes))
+		assert.Equal(t, "changed: 2024-01-06 Epiphany", report.Changes[0].String())
+		assert.Equal(t, "added: 2024-05-05 Liberation Day", report.Changes[1].String())
+		assert.Equal(t, "removed: 2024-05-01 Labour Day", report.Changes[2].String())
+
+		entries, err := ListCache()
+		assert.Nil(t, err)
+		assert.Equal(t, "token2", entries[0].Events.NextSyncToken)
+		assert.Equal(t, 3, len(entries[0].Events.Items))
+		assert.Equal(t, "Liberation Day", entries[0].Events.Items[2].Summary)
+		assert.Equal(t, "2024-01-01T00:00:00Z", entries[0].Ranges[0].FetchedAt.Format("2006-01-02T15:04:05Z07:00"))
+	})
+
+	t.Run("expired sync token", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = t.TempDir()
+		defer func() {
+			CacheDir = origDir
+		}()
+		syncTokens, status = nil, http.StatusGone
+
+		err := os.WriteFile(filepath.Join(CacheDir, "test.json"), []byte(cached), 0o600)
+		assert.Nil(t, err)
+
+		report, err := SyncCalendar("abc", "test")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"token1", ""}, syncTokens)
+		assert.True(t, report.FullResync)
+		assert.Equal(t, 2, len(report.Changes))
+		assert.Equal(t, "added: 2024-10-31 Reformation Day", report.Changes[0].String())
+		assert.Equal(t, "removed: 2024-01-06 Epiphany", report.Changes[1].String())
+
+		entries, err := ListCache()
+		assert.Nil(t, err)
+		assert.Equal(t, "token3", entries[0].Events.NextSyncToken)
+		assert.Equal(t, 1, len(entries[0].Ranges))
+		assert.Equal(t, "2024-12-31", entries[0].Ranges[0].End)
+	})
+
+	t.Run("failed sync", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = t.TempDir()
+		defer func() {
+			CacheDir = origDir
+		}()
+		syncTokens, status = nil, http.StatusForbidden
+
+		err := os.WriteFile(filepath.Join(CacheDir, "test.json"), []byte(cached), 0o600)
+		assert.Nil(t, err)
+
+		report, err := SyncCalendar("abc", "test")
+		assert.Equal(t, "failed to get events of calendar test - status code: 403", err.Error())
+		assert.Nil(t, report)
+	})
+
+	t.Run("canceled sync", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = t.TempDir()
+		defer func() {
+			CacheDir = origDir
+		}()
+		syncTokens, status = nil, http.StatusOK
+
+		filePath := filepath.Join(CacheDir, "test.json")
+		err := os.WriteFile(filePath, []byte(cached), 0o600)
+		assert.Nil(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		report, err := SyncCalendarContext(ctx, "abc", "test")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, report)
+		assert.Nil(t, syncTokens)
+
+		data, err := os.ReadFile(filePath)
+		assert.Nil(t, err)
+		assert.Equal(t, cached, string(data))
+	})
+
+	t.Run("calendar not cached", func(t *testing.T) {
+		origDir := CacheDir
+		CacheDir = t.TempDir()
+		defer func() {
+			CacheDir = origDir
+		}()
+
+		report, err := SyncCalendar("abc", "test")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Nil(t, report)
+	})
+}
+
+func TestApply(t *testing.T) {
+	entry := &CacheEntry{
+		Ranges: []*CacheRange{{Start: "2024-01-01", End: "2024-06-30"}, {Start: "2024-10-01", End: "2024-12-31"}},
+		Events: &Events{Items: []*Item{
+			{ID: "a", Summary: "New Year's Day", Start: EventTime{Date: "2024-01-01"}},
+			{ID: "b", Summary: "Company Day", Start: EventTime{Date: "2024-06-14"}},
+		}},
+	}
+
+	// events outside the cached ranges are not cached, also when they moved out of them
+	entry.apply(&Events{NextSyncToken: "token", Items: []*Item{
+		{ID: "b", Summary: "Company Day", Start: EventTime{Date: "2024-07-12"}},
+		{ID: "c", Summary: "Assumption Day", Start: EventTime{Date: "2024-08-15"}},
+		{ID: "d", Summary: "All Saints' Day", Start: EventTime{Date: "2024-11-01"}},
+		{ID: "e", Summary: "New Year's Day", Start: EventTime{Date: "2025-01-01"}},
+	}})
+
+	var ids []string
+	for _, i := range entry.Events.Items {
+		ids = append(ids, i.ID)
+	}
+	assert.Equal(t, []string{"a", "d"}, ids)
+	assert.Equal(t, "token", entry.Events.NextSyncToken)
+}
+
+func TestDiffItems(t *testing.T) {
+	previous := []*Item{
+		{Summary: "Christmas Day", Start: EventTime{Date: "2024-12-25"}},
+		{Summary: "Boxing Day", Start: EventTime{Date: "2024-12-26"}},
+	}
+	current := []*Item{
+		{Summary: "Christmas Day", Start: EventTime{Date: "2024-12-25"}},
+		{Summary: "Boxing Day", Start: EventTime{Date: "2024-12-27"}},
+	}
+
+	changes := diffItems(previous, current)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, ChangeAdded, changes[0].Type)
+	assert.Equal(t, "2024-12-27", getItemDate(changes[0].Item))
+	assert.Equal(t, ChangeRemoved, changes[1].Type)
+	assert.Equal(t, "2024-12-26", getItemDate(changes[1].Item))
+
+	assert.Nil(t, diffItems(previous, previous))
+}
@@ -0,0 +1,407 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+)
+
+var (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+
+	weekdays = map[string]time.Weekday{
+		"SU": time.Sunday,
+		"MO": time.Monday,
+		"TU": time.Tuesday,
+		"WE": time.Wednesday,
+		"TH": time.Thursday,
+		"FR": time.Friday,
+		"SA": time.Saturday,
+	}
+)
+
+// Calendar contains the events of an iCalendar file
+type Calendar struct {
+	Name   string
+	Events []*Event
+}
+
+// Event is a VEVENT of an iCalendar file. Start and End are dates, and End is exclusive.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	Start       time.Time
+	End         time.Time
+	Rule        *Rule
+	ExDates     []time.Time
+}
+
+// Rule is a yearly recurrence rule (RRULE) of an event
+type Rule struct {
+	Interval int
+	Count    int
+	Until    time.Time
+	// Month and Weekday are set for rules like "the second Sunday of May" (BYMONTH=5;BYDAY=2SU)
+	Month   time.Month
+	Weekday *time.Weekday
+	Nth     int
+}
+
+// property is a content line of an iCalendar file (e.g. "DTSTART;VALUE=DATE:20240101")
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the events of an iCalendar file
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &Calendar{}
+	var event *Event
+	// nested is the depth of the components inside the event (e.g. VALARM), whose properties are not the event's
+	var nested int
+	for _, line := range lines {
+		p := parseProperty(line)
+		switch {
+		case event != nil && nested > 0:
+			if p.name == "BEGIN" {
+				nested++
+			} else if p.name == "END" {
+				nested--
+			}
+		case event != nil && p.name == "BEGIN":
+			nested++
+		case p.name == "BEGIN" && p.value == "VEVENT":
+			event = &Event{}
+		case p.name == "END" && p.value == "VEVENT":
+			if event == nil || event.Start.IsZero() {
+				return nil, fmt.Errorf("invalid event: missing DTSTART")
+			}
+
+			if event.End.IsZero() || !event.End.After(event.Start) {
+				event.End = event.Start.AddDate(0, 0, 1)
+			}
+			calendar.Events = append(calendar.Events, event)
+			event = nil
+		case event == nil:
+			if p.name == "X-WR-CALNAME" {
+				calendar.Name = unescape(p.value)
+			}
+		default:
+			if err := event.set(p); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return calendar, nil
+}
+
+// set sets a property of an event
+func (e *Event) set(p *property) error {
+	var err error
+	switch p.name {
+	case "UID":
+		e.UID = p.value
+	case "SUMMARY":
+		e.Summary = unescape(p.value)
+	case "DESCRIPTION":
+		e.Description = unescape(p.value)
+	case "CATEGORIES":
+		for _, c := range strings.Split(p.value, ",") {
+			e.Categories = append(e.Categories, unescape(strings.TrimSpace(c)))
+		}
+	case "DTSTART":
+		e.Start, err = parseDate(p)
+	case "DTEND":
+		var end time.Time
+		if end, err = parseTime(p); err == nil {
+			// the end of an all-day event or of a timed event at midnight is exclusive
+			e.End = toDate(end)
+			if end.Hour() != 0 || end.Minute() != 0 || end.Second() != 0 {
+				e.End = e.End.AddDate(0, 0, 1)
+			}
+		}
+	case "RRULE":
+		e.Rule, err = parseRule(p.value)
+	case "EXDATE":
+		for _, v := range strings.Split(p.value, ",") {
+			var date time.Time
+			if date, err = parseDate(&property{params: p.params, value: v}); err != nil {
+				break
+			}
+			e.ExDates = append(e.ExDates, date)
+		}
+	}
+
+	return err
+}
+
+// GetHolidays returns a holiday for each date of the events from start to end (inclusive), with recurring events
+// repeated every year. Events are public holidays unless one of their categories is "Observance".
+func (c *Calendar) GetHolidays(start, end time.Time) []*planner.Holiday {
+	var holidays []*planner.Holiday
+	for _, e := range c.Events {
+		kind := e.getKind()
+		for _, occurrence := range e.getOccurrences(end) {
+			last := occurrence.Add(e.End.Sub(e.Start)).AddDate(0, 0, -1)
+			for d := occurrence; !d.After(last); d = d.AddDate(0, 0, 1) {
+				if !d.Before(start) && !d.After(end) {
+					holidays = append(holidays, &planner.Holiday{Date: d, Name: e.Summary, Kind: kind})
+				}
+			}
+		}
+	}
+
+	return holidays
+}
+
+// getKind returns the kind of holiday of an event based on its categories
+func (e *Event) getKind() planner.HolidayKind {
+	for _, c := range e.Categories {
+		if strings.EqualFold(c, "observance") {
+			return planner.KindObservance
+		}
+	}
+
+	return planner.KindPublic
+}
+
+// getOccurrences returns the start dates of an event until end, without its excluded dates
+func (e *Event) getOccurrences(end time.Time) []time.Time {
+	if e.Rule == nil {
+		return []time.Time{e.Start}
+	}
+
+	excluded := map[time.Time]bool{}
+	for _, d := range e.ExDates {
+		excluded[d] = true
+	}
+
+	var occurrences []time.Time
+	count := 0
+	for year := e.Start.Year(); year <= end.Year(); year += e.Rule.Interval {
+		date, ok := e.Rule.getDate(e.Start, year)
+		if !ok || date.Before(e.Start) {
+			continue
+		}
+
+		if !e.Rule.Until.IsZero() && date.After(e.Rule.Until) {
+			break
+		}
+
+		count++
+		if e.Rule.Count > 0 && count > e.Rule.Count {
+			break
+		}
+
+		if !excluded[date] {
+			occurrences = append(occurrences, date)
+		}
+	}
+
+	return occurrences
+}
+
+// getDate returns the date of a yearly recurrence in a year, and false if there is none (e.g. February 29)
+func (r *Rule) getDate(start time.Time, year int) (time.Time, bool) {
+	if r.Weekday == nil {
+		month := start.Month()
+		if r.Month != 0 {
+			month = r.Month
+		}
+
+		date := time.Date(year, month, start.Day(), 0, 0, 0, 0, time.UTC)
+		return date, date.Month() == month
+	}
+
+	month := r.Month
+	if month == 0 {
+		month = start.Month()
+	}
+
+	if r.Nth < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		days := (int(last.Weekday()) - int(*r.Weekday) + 7) % 7
+		date := last.AddDate(0, 0, -days+(r.Nth+1)*7)
+		return date, date.Month() == month
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	days := (int(*r.Weekday) - int(first.Weekday()) + 7) % 7
+	date := first.AddDate(0, 0, days+(max(r.Nth, 1)-1)*7)
+	return date, date.Month() == month
+}
+
+// parseRule parses a yearly recurrence rule (e.g. "FREQ=YEARLY;BYMONTH=5;BYDAY=2SU")
+func parseRule(value string) (*Rule, error) {
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, v, _ := strings.Cut(part, "=")
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			if strings.ToUpper(v) != "YEARLY" {
+				return nil, fmt.Errorf("unsupported recurrence rule: %s", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(v)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("invalid recurrence interval: %s", v)
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(v)
+		case "UNTIL":
+			rule.Until, err = parseDate(&property{value: v})
+		case "BYMONTH":
+			var month int
+			month, err = strconv.Atoi(v)
+			rule.Month = time.Month(month)
+		case "BYDAY":
+			if strings.Contains(v, ",") {
+				return nil, fmt.Errorf("unsupported recurrence rule: %s", value)
+			}
+			err = rule.setWeekday(v)
+		case "WKST", "":
+			// the start of the week does not change yearly dates
+		default:
+			// other parts (e.g. BYMONTHDAY, BYSETPOS) would give other dates than the ones computed here
+			return nil, fmt.Errorf("unsupported recurrence rule: %s", value)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rule, nil
+}
+
+// setWeekday sets the nth weekday of a rule from a BYDAY value (e.g. "2SU", "-1MO")
+func (r *Rule) setWeekday(value string) error {
+	value = strings.ToUpper(value)
+	if len(value) < 2 {
+		return fmt.Errorf("unsupported recurrence weekday: %s", value)
+	}
+
+	weekday, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return fmt.Errorf("unsupported recurrence weekday: %s", value)
+	}
+
+	if n := value[:len(value)-2]; n != "" {
+		nth, err := strconv.Atoi(strings.TrimPrefix(n, "+"))
+		if err != nil {
+			return fmt.Errorf("unsupported recurrence weekday: %s", value)
+		}
+		r.Nth = nth
+	}
+	r.Weekday = &weekday
+
+	return nil
+}
+
+// parseDate returns the date of a DATE or DATE-TIME value, in the time zone of its TZID if any
+func parseDate(p *property) (time.Time, error) {
+	t, err := parseTime(p)
+	if err != nil {
+		return t, err
+	}
+
+	return toDate(t), nil
+}
+
+// parseTime returns the time of a DATE or DATE-TIME value, in the time zone of its TZID if any
+func parseTime(p *property) (time.Time, error) {
+	value := strings.TrimSpace(p.value)
+	if len(value) <= len(dateFormat) {
+		return time.Parse(dateFormat, value)
+	}
+
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc = l
+	}
+
+	return time.ParseInLocation(dateTimeFormat, strings.TrimSuffix(value, "Z"), loc)
+}
+
+// toDate returns the calendar date of a time at midnight UTC
+func toDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseProperty splits a content line into its name, parameters and value
+func parseProperty(line string) *property {
+	p := &property{params: map[string]string{}}
+	nameAndParams, value, _ := cutUnquoted(line, ':')
+	p.value = value
+
+	parts := strings.Split(nameAndParams, ";")
+	p.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(v, `"`)
+	}
+
+	return p
+}
+
+// cutUnquoted cuts a string around the first separator that is not within double quotes
+func cutUnquoted(s string, sep byte) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				return s[:i], s[i+1:], true
+			}
+		}
+	}
+
+	return s, "", false
+}
+
+// unfold reads the content lines of an iCalendar file, joining lines that continue with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// unescape replaces the escaped characters of a text value
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
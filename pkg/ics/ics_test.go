@@ -0,0 +1,152 @@
+package ics
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		f, err := os.Open("fixtures/company.ics")
+		assert.Nil(t, err)
+		defer f.Close()
+
+		calendar, err := Parse(f)
+		assert.Nil(t, err)
+		assert.Equal(t, "Company Holidays", calendar.Name)
+		assert.Equal(t, 4, len(calendar.Events))
+
+		assert.Equal(t, "new-year@example.com", calendar.Events[0].UID)
+		assert.Equal(t, []time.Time{date(2025, time.January, 1)}, calendar.Events[0].ExDates)
+		assert.Equal(t, 1, calendar.Events[0].Rule.Interval)
+
+		assert.Equal(t, "Office closed, enjoy the\nsummer", calendar.Events[1].Description)
+		assert.Equal(t, date(2024, time.August, 17), calendar.Events[1].End)
+
+		// events without DTEND last one day
+		assert.Equal(t, date(2023, time.May, 15), calendar.Events[2].End)
+		assert.Equal(t, time.May, calendar.Events[2].Rule.Month)
+		assert.Equal(t, time.Sunday, *calendar.Events[2].Rule.Weekday)
+		assert.Equal(t, 2, calendar.Events[2].Rule.Nth)
+
+		// folded lines are joined and timed events end on their last day
+		assert.Equal(t, "Christmas Party", calendar.Events[3].Summary)
+		assert.Equal(t, date(2024, time.December, 20), calendar.Events[3].Start)
+		assert.Equal(t, date(2024, time.December, 21), calendar.Events[3].End)
+	})
+
+	t.Run("alarms", func(t *testing.T) {
+		calendar, err := Parse(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Company Day\nDTSTART;VALUE=DATE:20240607\n" +
+			"BEGIN:VALARM\nACTION:DISPLAY\nSUMMARY:Reminder\nDESCRIPTION:Tomorrow is a day off\nTRIGGER:-P1D\nEND:VALARM\n" +
+			"DESCRIPTION:Office closed\nEND:VEVENT\nEND:VCALENDAR"))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(calendar.Events))
+
+		// the properties of the alarm are not the event's
+		assert.Equal(t, "Company Day", calendar.Events[0].Summary)
+		assert.Equal(t, "Office closed", calendar.Events[0].Description)
+		assert.Equal(t, date(2024, time.June, 7), calendar.Events[0].Start)
+	})
+
+	tests := []struct {
+		name     string
+		event    string
+		expected string
+	}{
+		{name: "missing start", event: "SUMMARY:Holiday", expected: "invalid event: missing DTSTART"},
+		{name: "invalid date", event: "DTSTART;VALUE=DATE:2024-01-01", expected: `parsing time "2024-01-01" as "20060102T150405": cannot parse "-01-01" as "01"`},
+		{name: "unknown time zone", event: "DTSTART;TZID=Mars/Olympus:20240101T090000", expected: "unknown time zone Mars/Olympus"},
+		{name: "unsupported recurrence", event: "DTSTART:20240101\nRRULE:FREQ=WEEKLY", expected: "unsupported recurrence rule: FREQ=WEEKLY"},
+		{name: "unsupported day of month", event: "DTSTART:20240101\nRRULE:FREQ=YEARLY;BYMONTH=11;BYMONTHDAY=1", expected: "unsupported recurrence rule: FREQ=YEARLY;BYMONTH=11;BYMONTHDAY=1"},
+		{name: "unsupported weekdays", event: "DTSTART:20240101\nRRULE:FREQ=YEARLY;BYMONTH=5;BYDAY=MO,TU", expected: "unsupported recurrence rule: FREQ=YEARLY;BYMONTH=5;BYDAY=MO,TU"},
+		{name: "invalid interval", event: "DTSTART:20240101\nRRULE:FREQ=YEARLY;INTERVAL=0", expected: "invalid recurrence interval: 0"},
+		{name: "invalid weekday", event: "DTSTART:20240101\nRRULE:FREQ=YEARLY;BYDAY=2XX", expected: "unsupported recurrence weekday: 2XX"},
+		{name: "invalid excluded date", event: "DTSTART:20240101\nEXDATE:20240101,2025", expected: `parsing time "2025" as "20060102": cannot parse "" as "01"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar, err := Parse(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VEVENT\n" + tt.event + "\nEND:VEVENT\nEND:VCALENDAR"))
+			assert.Equal(t, tt.expected, err.Error())
+			assert.Nil(t, calendar)
+		})
+	}
+}
+
+func TestGetHolidays(t *testing.T) {
+	f, err := os.Open("fixtures/company.ics")
+	assert.Nil(t, err)
+	defer f.Close()
+
+	calendar, err := Parse(f)
+	assert.Nil(t, err)
+
+	var holidays []string
+	for _, h := range calendar.GetHolidays(date(2024, time.January, 1), date(2025, time.June, 30)) {
+		holidays = append(holidays, h.Date.Format(planner.DefaultTimeFormat)+" "+h.Name+" ("+h.Kind.String()+")")
+	}
+
+	assert.Equal(t, []string{
+		"2024-01-01 New Year's Day (public holiday)",
+		"2024-08-12 Summer Break (public holiday)",
+		"2024-08-13 Summer Break (public holiday)",
+		"2024-08-14 Summer Break (public holiday)",
+		"2024-08-15 Summer Break (public holiday)",
+		"2024-08-16 Summer Break (public holiday)",
+		"2024-05-12 Mother's Day (observance)",
+		"2025-05-11 Mother's Day (observance)",
+		"2024-12-20 Christmas Party (public holiday)",
+	}, holidays)
+}
+
+func TestGetOccurrences(t *testing.T) {
+	leapDay := date(2024, time.February, 29)
+	tests := []struct {
+		name     string
+		event    *Event
+		expected []time.Time
+	}{
+		{
+			name:     "not recurring",
+			event:    &Event{Start: leapDay},
+			expected: []time.Time{leapDay},
+		},
+		{
+			name:     "leap day",
+			event:    &Event{Start: leapDay, Rule: &Rule{Interval: 1}},
+			expected: []time.Time{leapDay, date(2028, time.February, 29)},
+		},
+		{
+			name:     "interval and count",
+			event:    &Event{Start: date(2020, time.May, 1), Rule: &Rule{Interval: 2, Count: 3}},
+			expected: []time.Time{date(2020, time.May, 1), date(2022, time.May, 1), date(2024, time.May, 1)},
+		},
+		{
+			name:     "until",
+			event:    &Event{Start: date(2026, time.May, 1), Rule: &Rule{Interval: 1, Until: date(2027, time.May, 1)}},
+			expected: []time.Time{date(2026, time.May, 1), date(2027, time.May, 1)},
+		},
+		{
+			name: "last Monday",
+			event: &Event{Start: date(2025, time.May, 26), Rule: &Rule{
+				Interval: 1, Month: time.May, Weekday: func() *time.Weekday { d := time.Monday; return &d }(), Nth: -1,
+			}},
+			expected: []time.Time{date(2025, time.May, 26), date(2026, time.May, 25), date(2027, time.May, 31), date(2028, time.May, 29)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.event.getOccurrences(date(2028, time.December, 31)))
+		})
+	}
+}
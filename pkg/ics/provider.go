@@ -0,0 +1,76 @@
+package ics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/jvmistica/holiday-planner-go/pkg/transport"
+)
+
+// HTTPClient is the HTTP client of calendar URLs, which times out and retries the requests that failed temporarily
+var HTTPClient = transport.NewClient(transport.DefaultConfig())
+
+// Provider gets holidays from an iCalendar file or URL (e.g. a company holiday calendar)
+type Provider struct {
+	Location string
+}
+
+// NewProvider returns a provider of the holidays of a local iCalendar file or an http(s) URL
+func NewProvider(location string) *Provider {
+	return &Provider{Location: location}
+}
+
+// GetHolidays returns the holidays of the calendar from start to end (inclusive)
+func (p *Provider) GetHolidays(start, end time.Time) ([]*planner.Holiday, error) {
+	return p.GetHolidaysContext(context.Background(), start, end)
+}
+
+// GetHolidaysContext is GetHolidays with a context that cancels the request of a calendar URL
+func (p *Provider) GetHolidaysContext(ctx context.Context, start, end time.Time) ([]*planner.Holiday, error) {
+	calendar, err := LoadContext(ctx, p.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	return calendar.GetHolidays(start, end), nil
+}
+
+// Load reads an iCalendar file from a local path or an http(s) URL
+func Load(location string) (*Calendar, error) {
+	return LoadContext(context.Background(), location)
+}
+
+// LoadContext is Load with a context that cancels the request of a calendar URL
+func LoadContext(ctx context.Context, location string) (*Calendar, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return Parse(f)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get calendar - status code: %d", resp.StatusCode)
+	}
+
+	return Parse(resp.Body)
+}
@@ -0,0 +1,71 @@
+package ics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider(t *testing.T) {
+	data, err := os.ReadFile("fixtures/company.ics")
+	assert.Nil(t, err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/holidays.ics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(data)
+		assert.Nil(t, err)
+	}))
+	defer ts.Close()
+
+	t.Run("local file", func(t *testing.T) {
+		holidays, err := NewProvider("fixtures/company.ics").GetHolidays(date(2024, time.August, 1), date(2024, time.August, 31))
+		assert.Nil(t, err)
+		assert.Equal(t, 5, len(holidays))
+	})
+
+	t.Run("file does not exist", func(t *testing.T) {
+		holidays, err := NewProvider("fixtures/missing.ics").GetHolidays(date(2024, time.August, 1), date(2024, time.August, 31))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Nil(t, holidays)
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		vacations, _, err := planner.Suggest(NewProvider(ts.URL+"/holidays.ics"), "2024-08-01", "2024-08-31", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(vacations))
+		assert.Equal(t, "2024-08-10", vacations[0].Start.Format(planner.DefaultTimeFormat))
+		assert.Equal(t, 9, vacations[0].Count)
+	})
+
+	t.Run("URL not found", func(t *testing.T) {
+		holidays, err := NewProvider(ts.URL+"/missing.ics").GetHolidays(date(2024, time.August, 1), date(2024, time.August, 31))
+		assert.Equal(t, "failed to get calendar - status code: 404", err.Error())
+		assert.Nil(t, holidays)
+	})
+
+	t.Run("canceled request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		holidays, err := NewProvider(ts.URL+"/holidays.ics").GetHolidaysContext(ctx, date(2024, time.August, 1), date(2024, time.August, 31))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, holidays)
+	})
+
+	t.Run("error querying URL", func(t *testing.T) {
+		holidays, err := NewProvider("http://invalid url").GetHolidays(date(2024, time.August, 1), date(2024, time.August, 31))
+		assert.NotNil(t, err)
+		assert.Nil(t, holidays)
+	})
+}
@@ -0,0 +1,117 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+)
+
+var (
+	// CategoryFree is the category of vacations that need no leave (e.g. long weekends)
+	CategoryFree = "Free"
+	// CategoryNeedsLeave is the category of suggested vacations that need leaves
+	CategoryNeedsLeave = "Needs leave"
+
+	productID     = "-//holiday-planner-go//Suggestions//EN"
+	uidDomain     = "holiday-planner-go"
+	maxLineLength = 75
+	now           = time.Now
+)
+
+// Write writes vacations without leaves and suggestions as all-day events of an iCalendar file.
+// Events have stable UIDs based on their dates, so that importing the file again updates them instead of adding duplicates.
+func Write(w io.Writer, name string, vacations []*planner.Vacation, suggestions []*planner.Suggestion) error {
+	b := bufio.NewWriter(w)
+	writeLine(b, "BEGIN:VCALENDAR")
+	writeLine(b, "VERSION:2.0")
+	writeLine(b, "PRODID:"+productID)
+	writeLine(b, "CALSCALE:GREGORIAN")
+	if name != "" {
+		writeLine(b, "X-WR-CALNAME:"+escape(name))
+	}
+
+	stamp := now().UTC().Format(dateTimeFormat + "Z")
+	for _, v := range vacations {
+		writeEvent(b, &Event{
+			UID:        getUID("free", v.Start, v.End),
+			Summary:    fmt.Sprintf("Long weekend (%d days)", v.Count),
+			Categories: []string{CategoryFree},
+			Start:      v.Start,
+			End:        v.End.AddDate(0, 0, 1),
+		}, stamp)
+	}
+
+	for _, s := range suggestions {
+		var leaveDates []string
+		for _, d := range s.LeaveDates {
+			leaveDates = append(leaveDates, d.Format(planner.DefaultTimeFormat))
+		}
+
+		// a suggestion without leaves has no description
+		var description string
+		if len(leaveDates) > 0 {
+			description = "Leave needed on: " + strings.Join(leaveDates, ", ")
+		}
+
+		writeEvent(b, &Event{
+			UID:         getUID("leave", s.Start, s.End),
+			Summary:     fmt.Sprintf("Vacation: %d leaves for %d days", s.Leaves, s.Vacation),
+			Description: description,
+			Categories:  []string{CategoryNeedsLeave},
+			Start:       s.Start,
+			End:         s.End.AddDate(0, 0, 1),
+		}, stamp)
+	}
+	writeLine(b, "END:VCALENDAR")
+
+	return b.Flush()
+}
+
+// writeEvent writes an all-day event
+func writeEvent(b *bufio.Writer, e *Event, stamp string) {
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+e.UID)
+	writeLine(b, "DTSTAMP:"+stamp)
+	writeLine(b, "DTSTART;VALUE=DATE:"+e.Start.Format(dateFormat))
+	writeLine(b, "DTEND;VALUE=DATE:"+e.End.Format(dateFormat))
+	writeLine(b, "SUMMARY:"+escape(e.Summary))
+	if e.Description != "" {
+		writeLine(b, "DESCRIPTION:"+escape(e.Description))
+	}
+
+	var categories []string
+	for _, c := range e.Categories {
+		categories = append(categories, escape(c))
+	}
+	writeLine(b, "CATEGORIES:"+strings.Join(categories, ","))
+	writeLine(b, "TRANSP:TRANSPARENT")
+	writeLine(b, "END:VEVENT")
+}
+
+// writeLine writes a content line, folded into lines of at most 75 octets
+func writeLine(b *bufio.Writer, line string) {
+	for len(line) > maxLineLength {
+		cut := maxLineLength
+		// do not split a multi-byte character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n")
+		line = " " + line[cut:]
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// getUID returns the UID of an event from its kind and dates
+func getUID(kind string, start, end time.Time) string {
+	return fmt.Sprintf("%s-%s-%s@%s", kind, start.Format(dateFormat), end.Format(dateFormat), uidDomain)
+}
+
+// escape escapes the special characters of a text value
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,102 @@
+package ics
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	origNow := now
+	now = func() time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	defer func() {
+		now = origNow
+	}()
+
+	vacations := []*planner.Vacation{
+		{Start: date(2024, time.May, 18), End: date(2024, time.May, 20), Count: 3},
+	}
+	suggestions := []*planner.Suggestion{
+		{
+			Vacation:   4,
+			Leaves:     1,
+			Start:      date(2024, time.May, 9),
+			End:        date(2024, time.May, 12),
+			LeaveDates: []time.Time{date(2024, time.May, 10)},
+		},
+		{
+			Vacation:   11,
+			Leaves:     4,
+			Start:      date(2024, time.May, 18),
+			End:        date(2024, time.May, 28),
+			LeaveDates: []time.Time{date(2024, time.May, 21), date(2024, time.May, 22), date(2024, time.May, 23), date(2024, time.May, 24)},
+		},
+	}
+
+	var b bytes.Buffer
+	err := Write(&b, "Holidays in Austria", vacations, suggestions)
+	assert.Nil(t, err)
+
+	content := b.String()
+	assert.True(t, strings.HasPrefix(content, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.Contains(t, content, "UID:free-20240518-20240520@holiday-planner-go\r\nDTSTAMP:20240102T030405Z\r\n")
+	assert.Contains(t, content, "DTSTART;VALUE=DATE:20240518\r\nDTEND;VALUE=DATE:20240521\r\n")
+	assert.Contains(t, content, "CATEGORIES:Needs leave\r\n")
+
+	for _, line := range strings.Split(content, "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+	}
+
+	t.Run("read back", func(t *testing.T) {
+		calendar, err := Parse(strings.NewReader(content))
+		assert.Nil(t, err)
+		assert.Equal(t, "Holidays in Austria", calendar.Name)
+		assert.Equal(t, 3, len(calendar.Events))
+
+		assert.Equal(t, "Long weekend (3 days)", calendar.Events[0].Summary)
+		assert.Equal(t, []string{CategoryFree}, calendar.Events[0].Categories)
+
+		assert.Equal(t, "leave-20240509-20240512@holiday-planner-go", calendar.Events[1].UID)
+		assert.Equal(t, "Vacation: 1 leaves for 4 days", calendar.Events[1].Summary)
+		assert.Equal(t, "Leave needed on: 2024-05-10", calendar.Events[1].Description)
+		assert.Equal(t, date(2024, time.May, 13), calendar.Events[1].End)
+
+		// folded lines are joined again
+		assert.Equal(t, "Leave needed on: 2024-05-21, 2024-05-22, 2024-05-23, 2024-05-24", calendar.Events[2].Description)
+	})
+
+	t.Run("suggestion without leaves", func(t *testing.T) {
+		var other bytes.Buffer
+		err := Write(&other, "", nil, []*planner.Suggestion{{Vacation: 3, Start: date(2024, time.November, 1), End: date(2024, time.November, 3)}})
+		assert.Nil(t, err)
+		assert.NotContains(t, other.String(), "DESCRIPTION")
+		assert.NotContains(t, other.String(), "Leave needed on")
+	})
+
+	t.Run("stable UIDs", func(t *testing.T) {
+		var other bytes.Buffer
+		err := Write(&other, "", nil, suggestions[:1])
+		assert.Nil(t, err)
+		assert.Contains(t, other.String(), "UID:leave-20240509-20240512@holiday-planner-go\r\n")
+		assert.NotContains(t, other.String(), "X-WR-CALNAME")
+	})
+}
+
+func TestWriteLine(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	writeLine(w, "SUMMARY:"+strings.Repeat("ä", 40))
+	assert.Nil(t, w.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, 74, len(lines[0]))
+	assert.Equal(t, " "+strings.Repeat("ä", 7), lines[1])
+}
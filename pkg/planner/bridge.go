@@ -0,0 +1,92 @@
+package planner
+
+import (
+	"sort"
+	"time"
+)
+
+// getBridgeSuggestions returns the leaves that connect each cluster of free days without a weekend
+// (e.g. a Thursday holiday) to its nearest weekend. If both sides need the same number of leaves, both are suggested.
+func getBridgeSuggestions(freeTime, weekends []time.Time, opts *Options) []*Suggestion {
+	var suggestions []*Suggestion
+	clusters := getFreeBlocks(freeTime, 1)
+	weekendDays := toDateSet(weekends)
+
+	for i, c := range clusters {
+		if hasWeekend(c, weekendDays) {
+			continue
+		}
+
+		var options []*Suggestion
+		for j := i - 1; j >= 0; j-- {
+			if hasWeekend(clusters[j], weekendDays) {
+				options = append(options, newSuggestion(clusters[j].Start, c.End, freeTime))
+				break
+			}
+		}
+
+		for j := i + 1; j < len(clusters); j++ {
+			if hasWeekend(clusters[j], weekendDays) {
+				options = append(options, newSuggestion(c.Start, clusters[j].End, freeTime))
+				break
+			}
+		}
+
+		fewest := opts.MaxLeaves
+		for _, o := range options {
+			fewest = min(fewest, o.Leaves)
+		}
+
+		for _, o := range options {
+			if o.Leaves == fewest && opts.isWorthIt(o) {
+				suggestions = append(suggestions, o)
+			}
+		}
+	}
+
+	return suggestions
+}
+
+// hasWeekend checks if any date of a block of free days is a weekend
+func hasWeekend(block *Vacation, weekendDays map[time.Time]bool) bool {
+	for d := block.Start; !d.After(block.End); d = d.AddDate(0, 0, 1) {
+		if weekendDays[d] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newSuggestion returns a suggestion from start to end, where every date that is not in freeTime needs a leave
+func newSuggestion(start, end time.Time, freeTime []time.Time) *Suggestion {
+	leaveDates := getLeaveDates(start.AddDate(0, 0, -1), end.AddDate(0, 0, 1), freeTime)
+	return &Suggestion{
+		Vacation:   int(end.Sub(start).Hours()/24) + 1,
+		Leaves:     len(leaveDates),
+		Start:      start,
+		End:        end,
+		LeaveDates: leaveDates,
+	}
+}
+
+// mergeSuggestions returns the suggestions sorted by start date, without duplicates of the same dates
+func mergeSuggestions(lists ...[]*Suggestion) []*Suggestion {
+	var merged []*Suggestion
+	seen := map[[2]time.Time]bool{}
+	for _, list := range lists {
+		for _, s := range list {
+			key := [2]time.Time{s.Start, s.End}
+			if !seen[key] {
+				seen[key] = true
+				merged = append(merged, s)
+			}
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Start.Before(merged[j].Start)
+	})
+
+	return merged
+}
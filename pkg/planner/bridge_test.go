@@ -0,0 +1,83 @@
+package planner
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBridgeSuggestions(t *testing.T) {
+	t.Run("Thursday holiday", func(t *testing.T) {
+		holidays := `["2023-06-08T00:00:00Z"]`
+		weekends := `["2023-06-03T00:00:00Z", "2023-06-04T00:00:00Z", "2023-06-10T00:00:00Z", "2023-06-11T00:00:00Z"]`
+
+		var h, w []time.Time
+		assert.Nil(t, json.Unmarshal([]byte(holidays), &h))
+		assert.Nil(t, json.Unmarshal([]byte(weekends), &w))
+
+		result := getBridgeSuggestions(FormatFreeTime(h, w), w, DefaultOptions())
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, 4, result[0].Vacation)
+		assert.Equal(t, 1, result[0].Leaves)
+		assert.Equal(t, "2023-06-08", result[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-06-11", result[0].End.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-06-09", result[0].LeaveDates[0].Format(DefaultTimeFormat))
+	})
+
+	t.Run("Wednesday holiday", func(t *testing.T) {
+		holidays := `["2023-11-01T00:00:00Z"]`
+		weekends := `["2023-10-28T00:00:00Z", "2023-10-29T00:00:00Z", "2023-11-04T00:00:00Z", "2023-11-05T00:00:00Z"]`
+
+		var h, w []time.Time
+		assert.Nil(t, json.Unmarshal([]byte(holidays), &h))
+		assert.Nil(t, json.Unmarshal([]byte(weekends), &w))
+
+		result := getBridgeSuggestions(FormatFreeTime(h, w), w, DefaultOptions())
+		assert.Equal(t, 2, len(result))
+		assert.Equal(t, "2023-10-28", result[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-11-01", result[0].End.Format(DefaultTimeFormat))
+		assert.Equal(t, 2, result[0].Leaves)
+		assert.Equal(t, "2023-11-01", result[1].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-11-05", result[1].End.Format(DefaultTimeFormat))
+		assert.Equal(t, 2, result[1].Leaves)
+	})
+
+	t.Run("holiday next to a weekend", func(t *testing.T) {
+		holidays := `["2023-10-26T00:00:00Z"]`
+		weekends := `["2023-10-21T00:00:00Z", "2023-10-22T00:00:00Z", "2023-10-27T00:00:00Z", "2023-10-28T00:00:00Z"]`
+
+		var h, w []time.Time
+		assert.Nil(t, json.Unmarshal([]byte(holidays), &h))
+		assert.Nil(t, json.Unmarshal([]byte(weekends), &w))
+
+		result := getBridgeSuggestions(FormatFreeTime(h, w), w, DefaultOptions())
+		assert.Nil(t, result)
+	})
+
+	t.Run("no weekend nearby", func(t *testing.T) {
+		holidays := `["2023-06-08T00:00:00Z"]`
+
+		var h []time.Time
+		assert.Nil(t, json.Unmarshal([]byte(holidays), &h))
+
+		result := getBridgeSuggestions(h, nil, DefaultOptions())
+		assert.Nil(t, result)
+	})
+}
+
+func TestMergeSuggestions(t *testing.T) {
+	first := `[{"start": "2023-12-23T00:00:00Z", "end": "2024-01-01T00:00:00Z"}, {"start": "2023-12-30T00:00:00Z", "end": "2024-01-07T00:00:00Z"}]`
+	second := `[{"start": "2023-06-08T00:00:00Z", "end": "2023-06-11T00:00:00Z"}, {"start": "2023-12-23T00:00:00Z", "end": "2024-01-01T00:00:00Z"}]`
+
+	var a, b []*Suggestion
+	assert.Nil(t, json.Unmarshal([]byte(first), &a))
+	assert.Nil(t, json.Unmarshal([]byte(second), &b))
+
+	result := mergeSuggestions(a, b)
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, "2023-06-08", result[0].Start.Format(DefaultTimeFormat))
+	assert.Equal(t, "2023-12-23", result[1].Start.Format(DefaultTimeFormat))
+	assert.Equal(t, "2023-12-30", result[2].Start.Format(DefaultTimeFormat))
+}
@@ -0,0 +1,119 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Plan contains the combination of vacation windows that maximizes the days off for a leave budget
+type Plan struct {
+	Windows         []*Suggestion
+	DaysOff         int
+	LeavesSpent     int
+	LeavesRemaining int
+}
+
+// score is the value of a partial plan
+type score struct {
+	days   int
+	leaves int
+}
+
+// better checks if a score has more days off, or the same days off with fewer leaves
+func (s score) better(other score) bool {
+	return s.days > other.days || (s.days == other.days && s.leaves < other.leaves)
+}
+
+// Optimize returns the non-overlapping vacations and suggestions that give the most days off
+// without spending more than the leave budget
+func Optimize(vacations []*Vacation, suggestions []*Suggestion, budget int) (*Plan, error) {
+	if budget < 0 {
+		return nil, fmt.Errorf("invalid leave budget: %d", budget)
+	}
+
+	windows := getWindows(vacations, suggestions)
+	previous := getPreviousWindows(windows)
+
+	// best[i][b] is the best score using the first i windows and at most b leaves
+	best := make([][]score, len(windows)+1)
+	taken := make([][]bool, len(windows)+1)
+	for i := range best {
+		best[i] = make([]score, budget+1)
+		taken[i] = make([]bool, budget+1)
+	}
+
+	for i, w := range windows {
+		for b := 0; b <= budget; b++ {
+			best[i+1][b] = best[i][b]
+			if w.Leaves > b {
+				continue
+			}
+
+			prev := best[previous[i]+1][b-w.Leaves]
+			candidate := score{days: prev.days + w.Vacation, leaves: prev.leaves + w.Leaves}
+			if candidate.better(best[i+1][b]) {
+				best[i+1][b] = candidate
+				taken[i+1][b] = true
+			}
+		}
+	}
+
+	plan := &Plan{}
+	for i, b := len(windows)-1, budget; i >= 0; {
+		if !taken[i+1][b] {
+			i--
+			continue
+		}
+
+		plan.Windows = append([]*Suggestion{windows[i]}, plan.Windows...)
+		b -= windows[i].Leaves
+		i = previous[i]
+	}
+
+	for _, w := range plan.Windows {
+		plan.DaysOff += w.Vacation
+		plan.LeavesSpent += w.Leaves
+	}
+	plan.LeavesRemaining = budget - plan.LeavesSpent
+
+	return plan, nil
+}
+
+// getWindows returns the vacations and suggestions as a single list sorted by end date
+func getWindows(vacations []*Vacation, suggestions []*Suggestion) []*Suggestion {
+	var windows []*Suggestion
+	for _, v := range vacations {
+		windows = append(windows, &Suggestion{
+			Vacation: v.Count,
+			Start:    v.Start,
+			End:      v.End,
+			Holidays: v.Holidays,
+		})
+	}
+	windows = append(windows, suggestions...)
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		if windows[i].End.Equal(windows[j].End) {
+			return windows[i].Start.Before(windows[j].Start)
+		}
+		return windows[i].End.Before(windows[j].End)
+	})
+
+	return windows
+}
+
+// getPreviousWindows returns, for each window, the index of the last window that ends before it starts (-1 if none)
+func getPreviousWindows(windows []*Suggestion) []int {
+	previous := make([]int, len(windows))
+	for i, w := range windows {
+		previous[i] = -1
+		for j := i - 1; j >= 0; j-- {
+			if windows[j].End.Before(w.Start) {
+				previous[i] = j
+				break
+			}
+		}
+	}
+
+	return previous
+}
@@ -0,0 +1,81 @@
+package planner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimize(t *testing.T) {
+	vacationDates := `[{"start": "2023-12-23T00:00:00Z", "end": "2023-12-26T00:00:00Z", "count": 4},
+		{"start": "2023-12-30T00:00:00Z", "end": "2024-01-01T00:00:00Z", "count": 3},
+		{"start": "2024-01-06T00:00:00Z", "end": "2024-01-07T00:00:00Z", "count": 2}]`
+	suggestionDates := `[{"vacation": 10, "leaves": 3, "start": "2023-12-23T00:00:00Z", "end": "2024-01-01T00:00:00Z"},
+		{"vacation": 9, "leaves": 4, "start": "2023-12-30T00:00:00Z", "end": "2024-01-07T00:00:00Z"}]`
+
+	var vacations []*Vacation
+	err := json.Unmarshal([]byte(vacationDates), &vacations)
+	assert.Nil(t, err)
+
+	var suggestions []*Suggestion
+	err = json.Unmarshal([]byte(suggestionDates), &suggestions)
+	assert.Nil(t, err)
+
+	t.Run("invalid budget", func(t *testing.T) {
+		plan, err := Optimize(vacations, suggestions, -1)
+		assert.Equal(t, "invalid leave budget: -1", err.Error())
+		assert.Nil(t, plan)
+	})
+
+	t.Run("no budget", func(t *testing.T) {
+		plan, err := Optimize(vacations, suggestions, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(plan.Windows))
+		assert.Equal(t, 9, plan.DaysOff)
+		assert.Equal(t, 0, plan.LeavesSpent)
+		assert.Equal(t, 0, plan.LeavesRemaining)
+	})
+
+	t.Run("budget for one of two overlapping suggestions", func(t *testing.T) {
+		plan, err := Optimize(vacations, suggestions, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(plan.Windows))
+		assert.Equal(t, "2023-12-23", plan.Windows[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-12-26", plan.Windows[0].End.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-12-30", plan.Windows[1].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2024-01-07", plan.Windows[1].End.Format(DefaultTimeFormat))
+		assert.Equal(t, 13, plan.DaysOff)
+		assert.Equal(t, 4, plan.LeavesSpent)
+		assert.Equal(t, 0, plan.LeavesRemaining)
+	})
+
+	t.Run("budget for one suggestion", func(t *testing.T) {
+		plan, err := Optimize(vacations, suggestions, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(plan.Windows))
+		assert.Equal(t, "2023-12-23", plan.Windows[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2024-01-01", plan.Windows[0].End.Format(DefaultTimeFormat))
+		assert.Equal(t, "2024-01-06", plan.Windows[1].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, 12, plan.DaysOff)
+		assert.Equal(t, 3, plan.LeavesSpent)
+		assert.Equal(t, 0, plan.LeavesRemaining)
+	})
+
+	t.Run("budget smaller than any suggestion", func(t *testing.T) {
+		plan, err := Optimize(vacations, suggestions, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(plan.Windows))
+		assert.Equal(t, 9, plan.DaysOff)
+		assert.Equal(t, 0, plan.LeavesSpent)
+		assert.Equal(t, 2, plan.LeavesRemaining)
+	})
+
+	t.Run("no vacations", func(t *testing.T) {
+		plan, err := Optimize(nil, nil, 25)
+		assert.Nil(t, err)
+		assert.Nil(t, plan.Windows)
+		assert.Equal(t, 0, plan.DaysOff)
+		assert.Equal(t, 25, plan.LeavesRemaining)
+	})
+}
@@ -0,0 +1,72 @@
+package planner
+
+import (
+	"fmt"
+	"time"
+)
+
+// Options contains the settings used to compute free time and vacation suggestions
+type Options struct {
+	// Observances are treated as days off, by name, date (YYYY-MM-DD) or yearly date (MM-DD)
+	Observances []string
+	// WorkWeek contains the non-working weekdays, Saturdays and Sundays if nil
+	WorkWeek *WorkWeek
+	// TakenLeaves are the dates of leaves that are already booked, which are free time.
+	// Vacations and suggestions that include them are not made again.
+	TakenLeaves []time.Time
+	// MinBlockDays is the least number of consecutive free days that make a vacation without leaves (default 3)
+	MinBlockDays int
+	// MaxLeaves is the most leaves a suggestion can need (default 5)
+	MaxLeaves int
+	// MinRatio is the least number of days off per leave a suggestion must give (default 0, no minimum)
+	MinRatio float64
+	// MinFreeDays is the least number of days off a suggestion must give on top of its leaves (default 3)
+	MinFreeDays int
+	// MinTripDays is the shortest suggestion in days (default 0, no minimum)
+	MinTripDays int
+	// MaxTripDays is the longest suggestion in days (default 0, no maximum)
+	MaxTripDays int
+}
+
+// DefaultOptions returns the options that only count public holidays and Saturdays and Sundays as free time,
+// and suggest vacations of up to 5 leaves that connect long weekends of at least 3 days
+func DefaultOptions() *Options {
+	return &Options{
+		MinBlockDays: defaultMinDaysWithoutLeave,
+		MaxLeaves:    defaultMaxLeaves,
+		MinFreeDays:  defaultMinFreeDays,
+	}
+}
+
+// Validate checks if the options are within their allowed ranges
+func (o *Options) Validate() error {
+	switch {
+	case o.MinBlockDays < 1:
+		return fmt.Errorf("invalid minimum block length: %d", o.MinBlockDays)
+	case o.MaxLeaves < 0:
+		return fmt.Errorf("invalid maximum leaves: %d", o.MaxLeaves)
+	case o.MinRatio < 0:
+		return fmt.Errorf("invalid minimum ratio: %g", o.MinRatio)
+	case o.MinFreeDays < 0:
+		return fmt.Errorf("invalid minimum free days: %d", o.MinFreeDays)
+	case o.MinTripDays < 0:
+		return fmt.Errorf("invalid minimum trip length: %d", o.MinTripDays)
+	case o.MaxTripDays < 0, o.MaxTripDays > 0 && o.MaxTripDays < o.MinTripDays:
+		return fmt.Errorf("invalid maximum trip length: %d", o.MaxTripDays)
+	}
+
+	return nil
+}
+
+// isWorthIt checks if a suggestion needs few enough leaves for the days off it gives and has an allowed length
+func (o *Options) isWorthIt(s *Suggestion) bool {
+	switch {
+	case s.Leaves > o.MaxLeaves,
+		s.Vacation-s.Leaves < o.MinFreeDays,
+		s.Vacation < o.MinTripDays,
+		o.MaxTripDays > 0 && s.Vacation > o.MaxTripDays:
+		return false
+	}
+
+	return s.Leaves == 0 || float64(s.Vacation)/float64(s.Leaves) >= o.MinRatio
+}
@@ -0,0 +1,70 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(o *Options)
+		expected string
+	}{
+		{name: "defaults", modify: func(o *Options) {}},
+		{name: "min block days", modify: func(o *Options) { o.MinBlockDays = 0 }, expected: "invalid minimum block length: 0"},
+		{name: "max leaves", modify: func(o *Options) { o.MaxLeaves = -1 }, expected: "invalid maximum leaves: -1"},
+		{name: "min ratio", modify: func(o *Options) { o.MinRatio = -0.5 }, expected: "invalid minimum ratio: -0.5"},
+		{name: "min free days", modify: func(o *Options) { o.MinFreeDays = -1 }, expected: "invalid minimum free days: -1"},
+		{name: "min trip days", modify: func(o *Options) { o.MinTripDays = -1 }, expected: "invalid minimum trip length: -1"},
+		{name: "max trip days", modify: func(o *Options) { o.MaxTripDays = -1 }, expected: "invalid maximum trip length: -1"},
+		{
+			name: "max trip days shorter than min trip days",
+			modify: func(o *Options) {
+				o.MinTripDays = 10
+				o.MaxTripDays = 5
+			},
+			expected: "invalid maximum trip length: 5",
+		},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		tt.modify(opts)
+
+		err := opts.Validate()
+		if tt.expected == "" {
+			assert.Nil(t, err, tt.name)
+		} else {
+			assert.Equal(t, tt.expected, err.Error(), tt.name)
+		}
+	}
+}
+
+func TestIsWorthIt(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(o *Options)
+		vacation int
+		leaves   int
+		expected bool
+	}{
+		{name: "defaults", modify: func(o *Options) {}, vacation: 9, leaves: 5, expected: true},
+		{name: "too many leaves", modify: func(o *Options) {}, vacation: 10, leaves: 6, expected: false},
+		{name: "too few free days", modify: func(o *Options) {}, vacation: 2, leaves: 1, expected: false},
+		// a leave must give at least 3 days off, as vacation-leaves > 1 did when the first day was not counted
+		{name: "3 days for 1 leave", modify: func(o *Options) {}, vacation: 3, leaves: 1, expected: false},
+		{name: "4 days for 1 leave", modify: func(o *Options) {}, vacation: 4, leaves: 1, expected: true},
+		{name: "ratio too low", modify: func(o *Options) { o.MinRatio = 2 }, vacation: 9, leaves: 5, expected: false},
+		{name: "no leaves", modify: func(o *Options) { o.MinRatio = 2 }, vacation: 4, leaves: 0, expected: true},
+		{name: "trip too short", modify: func(o *Options) { o.MinTripDays = 5 }, vacation: 4, leaves: 1, expected: false},
+		{name: "trip too long", modify: func(o *Options) { o.MaxTripDays = 7 }, vacation: 9, leaves: 5, expected: false},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		tt.modify(opts)
+		assert.Equal(t, tt.expected, opts.isWorthIt(&Suggestion{Vacation: tt.vacation, Leaves: tt.leaves}), tt.name)
+	}
+}
@@ -0,0 +1,450 @@
+package planner
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	DefaultTimeFormat = "2006-01-02"
+
+	defaultMinDaysWithoutLeave = 3
+	defaultMaxLeaves           = 5
+	defaultMinFreeDays         = 3
+	yearlyDateFormat           = "01-02"
+)
+
+// HolidayProvider is a source of holidays (e.g. Google Calendar, built-in rules)
+type HolidayProvider interface {
+	// GetHolidays returns the holidays from start to end (inclusive)
+	GetHolidays(start, end time.Time) ([]*Holiday, error)
+}
+
+// ContextHolidayProvider is a HolidayProvider that stops getting holidays (e.g. a network request) when a context is done
+type ContextHolidayProvider interface {
+	HolidayProvider
+	// GetHolidaysContext returns the holidays from start to end (inclusive), or the error of ctx if it is done first
+	GetHolidaysContext(ctx context.Context, start, end time.Time) ([]*Holiday, error)
+}
+
+// HolidayKind is the classification of a calendar event
+type HolidayKind int
+
+const (
+	// KindPublic is a public holiday, i.e. a non-working day
+	KindPublic HolidayKind = iota
+	// KindObservance is an observance that is usually a working day (e.g. Father's Day)
+	KindObservance
+	// KindOther is an event with a description that is neither of the above
+	KindOther
+)
+
+// String returns the name of the holiday kind
+func (k HolidayKind) String() string {
+	switch k {
+	case KindPublic:
+		return "public holiday"
+	case KindObservance:
+		return "observance"
+	default:
+		return "other"
+	}
+}
+
+// Holiday contains the details of a single holiday
+type Holiday struct {
+	Date time.Time
+	Name string
+	Kind HolidayKind
+	// Region is the region code (ISO 3166-2) of a regional holiday, empty if it applies to the whole calendar
+	Region string
+}
+
+// Suggestion contains the details of suggested vacation dates
+type Suggestion struct {
+	Vacation   int
+	Leaves     int
+	Start      time.Time
+	End        time.Time
+	LeaveDates []time.Time
+	// Holidays are the days off from Start to End that are holidays
+	Holidays []*Holiday
+}
+
+// Vacation contains the details of vacation dates (long weekends, etc.)
+type Vacation struct {
+	Start time.Time
+	End   time.Time
+	Count int
+	// Holidays are the days off from Start to End that are holidays
+	Holidays []*Holiday
+}
+
+// Suggest returns the vacations without leaves and suggested vacation leaves from start to end,
+// using the holidays of a provider. If opts is nil, DefaultOptions is used.
+func Suggest(provider HolidayProvider, start, end string, opts *Options) ([]*Vacation, []*Suggestion, error) {
+	return SuggestContext(context.Background(), provider, start, end, opts)
+}
+
+// SuggestContext is Suggest with a context that stops getting holidays when it is done, if the provider is a
+// ContextHolidayProvider
+func SuggestContext(ctx context.Context, provider HolidayProvider, start, end string, opts *Options) ([]*Vacation, []*Suggestion, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	startDate, err := time.Parse(DefaultTimeFormat, start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endDate, err := time.Parse(DefaultTimeFormat, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	holidays, err := getHolidays(ctx, provider, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return PlanHolidays(holidays, start, end, opts)
+}
+
+// getHolidays returns the holidays of a provider from start to end, with ctx if the provider supports it
+func getHolidays(ctx context.Context, provider HolidayProvider, start, end time.Time) ([]*Holiday, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if p, ok := provider.(ContextHolidayProvider); ok {
+		return p.GetHolidaysContext(ctx, start, end)
+	}
+
+	return provider.GetHolidays(start, end)
+}
+
+// PlanHolidays returns the vacations without leaves and suggested vacation leaves from start to end for a list of
+// holidays from any source, in the same way as Suggest. If opts is nil, DefaultOptions is used.
+func PlanHolidays(holidays []*Holiday, start, end string, opts *Options) ([]*Vacation, []*Suggestion, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	startDate, err := time.Parse(DefaultTimeFormat, start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endDate, err := time.Parse(DefaultTimeFormat, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	weekends, err := GetWeekends(start, end, opts.WorkWeek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	daysOff := getDayOffHolidays(filterHolidays(holidays, startDate, endDate), opts.Observances)
+	taken := filterDates(opts.TakenLeaves, startDate, endDate)
+	freeTime := FormatFreeTime(append(getDates(daysOff), taken...), weekends)
+	vacationWithoutLeaves := GetVacationsWithoutLeaves(freeTime, opts.MinBlockDays)
+	suggestions := getParetoOptimal(mergeSuggestions(
+		GetSuggestions(vacationWithoutLeaves, freeTime, opts),
+		getBridgeSuggestions(freeTime, weekends, opts),
+	))
+
+	// vacations and suggestions with booked leaves are already planned
+	if len(taken) > 0 {
+		vacationWithoutLeaves, suggestions = withoutTakenLeaves(vacationWithoutLeaves, suggestions, taken)
+	}
+
+	for _, v := range vacationWithoutLeaves {
+		v.Holidays = getHolidaysBetween(daysOff, v.Start, v.End)
+	}
+
+	for _, s := range suggestions {
+		s.Holidays = getHolidaysBetween(daysOff, s.Start, s.End)
+	}
+
+	return vacationWithoutLeaves, suggestions, nil
+}
+
+// filterHolidays returns the holidays from start to end (inclusive)
+func filterHolidays(holidays []*Holiday, start, end time.Time) []*Holiday {
+	var filtered []*Holiday
+	for _, h := range holidays {
+		if !h.Date.Before(start) && !h.Date.After(end) {
+			filtered = append(filtered, h)
+		}
+	}
+
+	return filtered
+}
+
+// filterDates returns the dates from start to end (inclusive)
+func filterDates(dates []time.Time, start, end time.Time) []time.Time {
+	var filtered []time.Time
+	for _, d := range dates {
+		if !d.Before(start) && !d.After(end) {
+			filtered = append(filtered, d)
+		}
+	}
+
+	return filtered
+}
+
+// CountTakenLeaves returns the number of distinct taken leaves from start to end (YYYY-MM-DD, inclusive)
+func CountTakenLeaves(takenLeaves []time.Time, start, end string) (int, error) {
+	startDate, err := time.Parse(DefaultTimeFormat, start)
+	if err != nil {
+		return 0, err
+	}
+
+	endDate, err := time.Parse(DefaultTimeFormat, end)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(toDateSet(filterDates(takenLeaves, startDate, endDate))), nil
+}
+
+// withoutTakenLeaves returns the vacations and suggestions that include none of the taken leaves
+func withoutTakenLeaves(vacations []*Vacation, suggestions []*Suggestion, taken []time.Time) ([]*Vacation, []*Suggestion) {
+	var freeVacations []*Vacation
+	for _, v := range vacations {
+		if len(filterDates(taken, v.Start, v.End)) == 0 {
+			freeVacations = append(freeVacations, v)
+		}
+	}
+
+	var freeSuggestions []*Suggestion
+	for _, s := range suggestions {
+		if len(filterDates(taken, s.Start, s.End)) == 0 {
+			freeSuggestions = append(freeSuggestions, s)
+		}
+	}
+
+	return freeVacations, freeSuggestions
+}
+
+// GetDaysOff returns the dates of public holidays and of the observances opted in by name or date
+func GetDaysOff(holidays []*Holiday, observances []string) []time.Time {
+	return getDates(getDayOffHolidays(holidays, observances))
+}
+
+// getDayOffHolidays returns the public holidays and the observances opted in by name or date
+func getDayOffHolidays(holidays []*Holiday, observances []string) []*Holiday {
+	var daysOff []*Holiday
+	for _, h := range holidays {
+		if h.Kind == KindPublic || isOptedIn(h, observances) {
+			daysOff = append(daysOff, h)
+		}
+	}
+
+	return daysOff
+}
+
+// getDates returns the dates of holidays
+func getDates(holidays []*Holiday) []time.Time {
+	var dates []time.Time
+	for _, h := range holidays {
+		dates = append(dates, h.Date)
+	}
+
+	return dates
+}
+
+// getHolidaysBetween returns the holidays from start to end (inclusive), sorted by date
+func getHolidaysBetween(holidays []*Holiday, start, end time.Time) []*Holiday {
+	between := filterHolidays(holidays, start, end)
+	sort.SliceStable(between, func(i, j int) bool {
+		return between[i].Date.Before(between[j].Date)
+	})
+
+	return between
+}
+
+// isOptedIn checks if a holiday's name, date (YYYY-MM-DD) or yearly date (MM-DD) is in the list of observances
+func isOptedIn(holiday *Holiday, observances []string) bool {
+	for _, o := range observances {
+		o = strings.TrimSpace(o)
+		if strings.EqualFold(o, holiday.Name) ||
+			o == holiday.Date.Format(DefaultTimeFormat) ||
+			o == holiday.Date.Format(yearlyDateFormat) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetWeekends returns a list of dates that fall on non-working weekdays (Saturdays and Sundays if workWeek is nil)
+func GetWeekends(startDate, endDate string, workWeek *WorkWeek) ([]time.Time, error) {
+	var weekends []time.Time
+	start, err := time.Parse(DefaultTimeFormat, startDate)
+	if err != nil {
+		return weekends, err
+	}
+
+	end, err := time.Parse(DefaultTimeFormat, endDate)
+	if err != nil {
+		return weekends, err
+	}
+
+	if workWeek == nil {
+		if workWeek, err = ParseWorkWeek(DefaultWeekend, ""); err != nil {
+			return weekends, err
+		}
+	}
+
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !workWeek.IsWorkingDay(d) {
+			weekends = append(weekends, d)
+		}
+	}
+
+	return weekends, nil
+}
+
+// GetVacationsWithoutLeaves returns free time of minDays (3 by default) or more days where filing a vacation leave
+// is not needed (i.e. long weekends)
+func GetVacationsWithoutLeaves(freeTime []time.Time, minDays int) []*Vacation {
+	return getFreeBlocks(freeTime, minDays)
+}
+
+// getFreeBlocks returns the blocks of consecutive dates in freeTime that are at least minDays long
+func getFreeBlocks(freeTime []time.Time, minDays int) []*Vacation {
+	var fromDate time.Time
+	var dates []*Vacation
+	days := 0
+
+	for i := range freeTime {
+		if days == 0 {
+			fromDate = freeTime[i]
+		}
+
+		days += 1
+		if i == len(freeTime)-1 || freeTime[i].AddDate(0, 0, 1) != freeTime[i+1] {
+			if days >= minDays {
+				dates = append(dates, &Vacation{
+					Start: fromDate,
+					End:   freeTime[i],
+					Count: days,
+				})
+			}
+			days = 0
+		}
+	}
+
+	return dates
+}
+
+// GetSuggestions returns a list of suggested vacation dates spanning two or more consecutive vacations.
+// Days between the vacations that are in freeTime (e.g. a single holiday or a one-day weekend) do not need a leave.
+func GetSuggestions(pairs []*Vacation, freeTime []time.Time, opts *Options) []*Suggestion {
+	var suggestions []*Suggestion
+	for i, d := range pairs {
+		var leaveDates []time.Time
+		for j := i + 1; j < len(pairs); j++ {
+			leaveDates = append(leaveDates, getLeaveDates(pairs[j-1].End, pairs[j].Start, freeTime)...)
+			if len(leaveDates) > opts.MaxLeaves {
+				break
+			}
+
+			suggestion := &Suggestion{
+				Vacation:   int(pairs[j].End.Sub(d.Start).Hours()/24) + 1,
+				Leaves:     len(leaveDates),
+				Start:      d.Start,
+				End:        pairs[j].End,
+				LeaveDates: append([]time.Time{}, leaveDates...),
+			}
+			if opts.isWorthIt(suggestion) {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+	}
+
+	return suggestions
+}
+
+// getParetoOptimal returns the suggestions for which no overlapping suggestion gives
+// more days off for the same or fewer leaves, or the same days off for fewer leaves
+func getParetoOptimal(suggestions []*Suggestion) []*Suggestion {
+	var optimal []*Suggestion
+	for _, s := range suggestions {
+		dominated := false
+		for _, other := range suggestions {
+			if other == s || other.Start.After(s.End) || other.End.Before(s.Start) {
+				continue
+			}
+
+			if other.Vacation >= s.Vacation && other.Leaves <= s.Leaves &&
+				(other.Vacation > s.Vacation || other.Leaves < s.Leaves) {
+				dominated = true
+				break
+			}
+		}
+
+		if !dominated {
+			optimal = append(optimal, s)
+		}
+	}
+
+	return optimal
+}
+
+// getLeaveDates returns the days strictly between from and to that are not in freeTime
+func getLeaveDates(from, to time.Time, freeTime []time.Time) []time.Time {
+	free := toDateSet(freeTime)
+
+	var leaves []time.Time
+	for d := from.AddDate(0, 0, 1); d.Before(to); d = d.AddDate(0, 0, 1) {
+		if !free[d] {
+			leaves = append(leaves, d)
+		}
+	}
+
+	return leaves
+}
+
+// toDateSet returns a set of the given dates
+func toDateSet(dates []time.Time) map[time.Time]bool {
+	set := map[time.Time]bool{}
+	for _, d := range dates {
+		set[d] = true
+	}
+
+	return set
+}
+
+// FormatFreeTime returns a sorted list of holidays and weekends combined
+func FormatFreeTime(holidays, weekends []time.Time) []time.Time {
+	var freeTime []time.Time
+	freeTime = append(freeTime, holidays...)
+	freeTime = append(freeTime, weekends...)
+
+	sort.Slice(freeTime, func(i, j int) bool {
+		return freeTime[i].Before(freeTime[j])
+	})
+
+	var newList []time.Time
+	for k, v := range freeTime {
+		if k == len(freeTime)-1 || v != freeTime[k+1] {
+			newList = append(newList, v)
+		}
+	}
+
+	return newList
+}
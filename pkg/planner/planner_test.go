@@ -0,0 +1,392 @@
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProvider returns a fixed list of holidays
+type fakeProvider struct {
+	holidays []*Holiday
+	err      error
+	start    time.Time
+	end      time.Time
+}
+
+// GetHolidays returns the holidays of the fake provider and records the requested dates
+func (p *fakeProvider) GetHolidays(start, end time.Time) ([]*Holiday, error) {
+	p.start, p.end = start, end
+	return p.holidays, p.err
+}
+
+// fakeContextProvider is a fake provider that records the context it was called with
+type fakeContextProvider struct {
+	fakeProvider
+	ctx context.Context
+}
+
+// GetHolidaysContext records the context and returns the holidays of the fake provider
+func (p *fakeContextProvider) GetHolidaysContext(ctx context.Context, start, end time.Time) ([]*Holiday, error) {
+	p.ctx = ctx
+	return p.GetHolidays(start, end)
+}
+
+func TestSuggest(t *testing.T) {
+	t.Run("invalid options", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.MaxLeaves = -1
+		vacations, suggestions, err := Suggest(&fakeProvider{}, "2023-05-01", "2023-05-31", opts)
+		assert.Equal(t, "invalid maximum leaves: -1", err.Error())
+		assert.Nil(t, vacations)
+		assert.Nil(t, suggestions)
+	})
+
+	t.Run("error parsing start and end dates", func(t *testing.T) {
+		_, _, err := Suggest(&fakeProvider{}, "2023/05/01", "2023-05-31", nil)
+		assert.NotNil(t, err)
+
+		_, _, err = Suggest(&fakeProvider{}, "2023-05-01", "2023/05/31", nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		vacations, suggestions, err := Suggest(&fakeProvider{err: errors.New("unavailable")}, "2023-05-01", "2023-05-31", nil)
+		assert.Equal(t, "unavailable", err.Error())
+		assert.Nil(t, vacations)
+		assert.Nil(t, suggestions)
+	})
+
+	t.Run("successful", func(t *testing.T) {
+		provider := &fakeProvider{holidays: []*Holiday{
+			{Date: time.Date(2023, 5, 18, 0, 0, 0, 0, time.UTC), Name: "Ascension Day", Kind: KindPublic},
+			{Date: time.Date(2023, 5, 29, 0, 0, 0, 0, time.UTC), Name: "Whit Monday", Kind: KindPublic},
+			{Date: time.Date(2023, 6, 8, 0, 0, 0, 0, time.UTC), Name: "Corpus Christi", Kind: KindPublic},
+		}}
+
+		vacations, suggestions, err := Suggest(provider, "2023-05-01", "2023-05-31", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "2023-05-01", provider.start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-05-31", provider.end.Format(DefaultTimeFormat))
+
+		// holidays after the end date are ignored
+		assert.Equal(t, 1, len(vacations))
+		assert.Equal(t, "2023-05-27", vacations[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, 1, len(suggestions))
+		assert.Equal(t, "2023-05-18", suggestions[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, 1, suggestions[0].Leaves)
+
+		// the holidays of each vacation are kept for its description
+		assert.Equal(t, "Whit Monday", vacations[0].Holidays[0].Name)
+		assert.Equal(t, 1, len(suggestions[0].Holidays))
+		assert.Equal(t, "Ascension Day", suggestions[0].Holidays[0].Name)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "run")
+		provider := &fakeContextProvider{}
+
+		_, _, err := SuggestContext(ctx, provider, "2023-05-01", "2023-05-31", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "run", provider.ctx.Value(ctxKey{}))
+
+		// a provider without context support is not called once the context is done
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		plain := &fakeProvider{}
+		vacations, suggestions, err := SuggestContext(canceled, plain, "2023-05-01", "2023-05-31", nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, vacations)
+		assert.Nil(t, suggestions)
+		assert.True(t, plain.start.IsZero())
+	})
+
+	t.Run("taken leaves", func(t *testing.T) {
+		provider := &fakeProvider{holidays: []*Holiday{
+			{Date: time.Date(2023, 5, 18, 0, 0, 0, 0, time.UTC), Name: "Ascension Day", Kind: KindPublic},
+			{Date: time.Date(2023, 5, 29, 0, 0, 0, 0, time.UTC), Name: "Whit Monday", Kind: KindPublic},
+		}}
+
+		opts := DefaultOptions()
+		opts.TakenLeaves = []time.Time{time.Date(2023, 5, 19, 0, 0, 0, 0, time.UTC), time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC)}
+		vacations, suggestions, err := Suggest(provider, "2023-05-01", "2023-05-31", opts)
+		assert.Nil(t, err)
+
+		// the booked long weekend of Ascension Day is neither a vacation nor a suggestion
+		assert.Equal(t, 1, len(vacations))
+		assert.Equal(t, "2023-05-27", vacations[0].Start.Format(DefaultTimeFormat))
+		assert.Empty(t, suggestions)
+	})
+}
+
+func TestCountTakenLeaves(t *testing.T) {
+	taken := []time.Time{
+		time.Date(2023, 5, 19, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 5, 19, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 5, 22, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	count, err := CountTakenLeaves(taken, "2023-05-01", "2023-05-31")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = CountTakenLeaves(taken, "2023/05/01", "2023-05-31")
+	assert.NotNil(t, err)
+
+	_, err = CountTakenLeaves(taken, "2023-05-01", "2023/05/31")
+	assert.NotNil(t, err)
+}
+
+func TestGetDaysOff(t *testing.T) {
+	holidays := []*Holiday{
+		{Date: time.Date(2023, 10, 26, 0, 0, 0, 0, time.UTC), Name: "National Day", Kind: KindPublic},
+		{Date: time.Date(2023, 10, 31, 0, 0, 0, 0, time.UTC), Name: "Reformation Day", Kind: KindObservance},
+		{Date: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), Name: "All Saints' Day", Kind: KindPublic},
+		{Date: time.Date(2023, 11, 2, 0, 0, 0, 0, time.UTC), Name: "All Souls' Day", Kind: KindObservance},
+		{Date: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), Name: "New Year's Eve", Kind: KindObservance},
+	}
+
+	t.Run("public holidays only", func(t *testing.T) {
+		days := GetDaysOff(holidays, nil)
+		assert.Equal(t, 2, len(days))
+		for _, d := range days {
+			assert.NotEqual(t, "2023-11-02", d.Format(DefaultTimeFormat))
+		}
+	})
+
+	t.Run("opt in by name", func(t *testing.T) {
+		days := GetDaysOff(holidays, []string{"all souls' day"})
+		assert.Equal(t, 3, len(days))
+		assert.Equal(t, "2023-11-02", days[2].Format(DefaultTimeFormat))
+	})
+
+	t.Run("opt in by date", func(t *testing.T) {
+		days := GetDaysOff(holidays, []string{"2023-12-31", "10-31"})
+		assert.Equal(t, 4, len(days))
+	})
+
+	t.Run("opt in a date that is not an observance", func(t *testing.T) {
+		days := GetDaysOff(holidays, []string{"12-24"})
+		assert.Equal(t, 2, len(days))
+	})
+}
+
+func TestGetWeekends(t *testing.T) {
+	tests := []struct {
+		startDate     string
+		endDate       string
+		workWeek      *WorkWeek
+		expectedCount int
+		wantErr       bool
+	}{
+		{
+			startDate:     "2023-05-01",
+			endDate:       "2023-05-31",
+			expectedCount: 8,
+		},
+		{
+			startDate:     "2023-05-01",
+			endDate:       "2023-05-31",
+			workWeek:      &WorkWeek{Weeks: []map[time.Weekday]bool{{time.Friday: true, time.Saturday: true}}},
+			expectedCount: 8,
+		},
+		{
+			startDate:     "2023-05-01",
+			endDate:       "2023-05-31",
+			workWeek:      &WorkWeek{Weeks: []map[time.Weekday]bool{{time.Sunday: true}}},
+			expectedCount: 4,
+		},
+		{
+			startDate: "2023-05-01",
+			endDate:   "2023-05-31",
+			workWeek: &WorkWeek{
+				Weeks: []map[time.Weekday]bool{
+					{time.Saturday: true, time.Sunday: true},
+					{time.Friday: true, time.Saturday: true, time.Sunday: true},
+				},
+				Anchor: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedCount: 10,
+		},
+		{
+			startDate:     "2023-12-15",
+			endDate:       "2024-01-15",
+			expectedCount: 10,
+		},
+		{
+			startDate: "2023/12/15",
+			endDate:   "2024-01-15",
+			wantErr:   true,
+		},
+		{
+			startDate: "2023-12-15",
+			endDate:   "2024/01/15",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		weekends, err := GetWeekends(tt.startDate, tt.endDate, tt.workWeek)
+		if tt.wantErr {
+			assert.Nil(t, weekends)
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedCount, len(weekends))
+		}
+	}
+}
+
+func TestGetVacationsWithoutLeaves(t *testing.T) {
+	dates := `["2023-05-15T00:00:00Z", "2023-05-27T00:00:00Z", "2023-05-28T00:00:00Z", "2023-05-29T00:00:00Z"]`
+
+	var free []time.Time
+	err := json.Unmarshal([]byte(dates), &free)
+	assert.Nil(t, err)
+
+	result := GetVacationsWithoutLeaves(free, defaultMinDaysWithoutLeave)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, 3, result[0].Count)
+
+	result = GetVacationsWithoutLeaves(free, 1)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, 1, result[0].Count)
+
+	result = GetVacationsWithoutLeaves(nil, defaultMinDaysWithoutLeave)
+	assert.Nil(t, result)
+}
+
+func TestGetSuggestions(t *testing.T) {
+	t.Run("one pair", func(t *testing.T) {
+		dates := `[{"start": "2023-05-24T00:00:00Z", "end": "2023-05-28T00:00:00Z"}]`
+
+		var free []*Vacation
+		err := json.Unmarshal([]byte(dates), &free)
+		assert.Nil(t, err)
+
+		result := GetSuggestions(free, nil, DefaultOptions())
+		assert.Nil(t, result)
+	})
+
+	t.Run("two pairs", func(t *testing.T) {
+		dates := `[{"start": "2023-12-23T00:00:00Z", "end": "2023-12-26T00:00:00Z"}, {"start": "2023-12-30T00:00:00Z", "end": "2024-01-01T00:00:00Z"}]`
+
+		var free []*Vacation
+		err := json.Unmarshal([]byte(dates), &free)
+		assert.Nil(t, err)
+
+		result := GetSuggestions(free, nil, DefaultOptions())
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, 10, result[0].Vacation)
+		assert.Equal(t, 3, result[0].Leaves)
+		assert.Equal(t, "2023-12-27", result[0].LeaveDates[0].Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-12-29", result[0].LeaveDates[2].Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-12-23", result[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2024-01-01", result[0].End.Format(DefaultTimeFormat))
+	})
+
+	t.Run("three pairs", func(t *testing.T) {
+		dates := `[{"start": "2023-05-22T00:00:00Z", "end": "2023-05-23T00:00:00Z"}, {"start": "2023-05-24T00:00:00Z", "end": "2023-05-25T00:00:00Z"}, {"start": "2023-05-27T00:00:00Z", "end": "2023-05-28T00:00:00Z"}]`
+
+		var free []*Vacation
+		err := json.Unmarshal([]byte(dates), &free)
+		assert.Nil(t, err)
+
+		result := GetSuggestions(free, nil, DefaultOptions())
+		assert.Equal(t, 3, len(result))
+		assert.Equal(t, 4, result[0].Vacation)
+		assert.Equal(t, 0, result[0].Leaves)
+		assert.Equal(t, "2023-05-22", result[0].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-05-25", result[0].End.Format(DefaultTimeFormat))
+		assert.Equal(t, 7, result[1].Vacation)
+		assert.Equal(t, 1, result[1].Leaves)
+		assert.Equal(t, "2023-05-22", result[1].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-05-28", result[1].End.Format(DefaultTimeFormat))
+		assert.Equal(t, 5, result[2].Vacation)
+		assert.Equal(t, 1, result[2].Leaves)
+		assert.Equal(t, "2023-05-24", result[2].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2023-05-28", result[2].End.Format(DefaultTimeFormat))
+	})
+
+	t.Run("chain from Christmas to Epiphany", func(t *testing.T) {
+		dates := `[{"start": "2023-12-23T00:00:00Z", "end": "2023-12-26T00:00:00Z"}, {"start": "2023-12-30T00:00:00Z", "end": "2024-01-01T00:00:00Z"}, {"start": "2024-01-06T00:00:00Z", "end": "2024-01-07T00:00:00Z"}]`
+
+		var free []*Vacation
+		err := json.Unmarshal([]byte(dates), &free)
+		assert.Nil(t, err)
+
+		opts := DefaultOptions()
+		opts.MaxLeaves = 7
+		result := GetSuggestions(free, nil, opts)
+		assert.Equal(t, 3, len(result))
+		assert.Equal(t, 16, result[1].Vacation)
+		assert.Equal(t, 7, result[1].Leaves)
+		assert.Equal(t, "2023-12-23", result[1].Start.Format(DefaultTimeFormat))
+		assert.Equal(t, "2024-01-07", result[1].End.Format(DefaultTimeFormat))
+
+		opts.MinRatio = 3
+		result = GetSuggestions(free, nil, opts)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, 10, result[0].Vacation)
+		assert.Equal(t, 3, result[0].Leaves)
+	})
+
+	t.Run("free day between pairs", func(t *testing.T) {
+		dates := `[{"start": "2023-12-23T00:00:00Z", "end": "2023-12-26T00:00:00Z"}, {"start": "2023-12-30T00:00:00Z", "end": "2024-01-01T00:00:00Z"}]`
+		freeDates := `["2023-12-28T00:00:00Z"]`
+
+		var free []*Vacation
+		err := json.Unmarshal([]byte(dates), &free)
+		assert.Nil(t, err)
+
+		var freeTime []time.Time
+		err = json.Unmarshal([]byte(freeDates), &freeTime)
+		assert.Nil(t, err)
+
+		result := GetSuggestions(free, freeTime, DefaultOptions())
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, 10, result[0].Vacation)
+		assert.Equal(t, 2, result[0].Leaves)
+	})
+}
+
+func TestGetParetoOptimal(t *testing.T) {
+	dates := `[{"vacation": 10, "leaves": 3, "start": "2023-12-23T00:00:00Z", "end": "2024-01-01T00:00:00Z"},
+		{"vacation": 16, "leaves": 7, "start": "2023-12-23T00:00:00Z", "end": "2024-01-07T00:00:00Z"},
+		{"vacation": 9, "leaves": 4, "start": "2023-12-30T00:00:00Z", "end": "2024-01-07T00:00:00Z"},
+		{"vacation": 4, "leaves": 1, "start": "2023-06-08T00:00:00Z", "end": "2023-06-11T00:00:00Z"}]`
+
+	var suggestions []*Suggestion
+	err := json.Unmarshal([]byte(dates), &suggestions)
+	assert.Nil(t, err)
+
+	result := getParetoOptimal(suggestions)
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, 10, result[0].Vacation)
+	assert.Equal(t, 16, result[1].Vacation)
+	assert.Equal(t, 4, result[2].Vacation)
+}
+
+func TestFormatFreeTime(t *testing.T) {
+	holidays := `["2023-12-25T00:00:00Z", "2023-12-26T00:00:00Z", "2023-12-21T00:00:00Z", "2024-01-01T00:00:00Z"]`
+	weekends := `["2023-12-23T00:00:00Z", "2023-12-24T00:00:00Z", "2023-12-30T00:00:00Z", "2023-12-31T00:00:00Z"]`
+
+	var h []time.Time
+	err := json.Unmarshal([]byte(holidays), &h)
+	assert.Nil(t, err)
+
+	var w []time.Time
+	err = json.Unmarshal([]byte(weekends), &w)
+	assert.Nil(t, err)
+
+	result := FormatFreeTime(h, w)
+	assert.Equal(t, 8, len(result))
+	assert.Equal(t, "2023-12-21", result[0].Format(DefaultTimeFormat))
+	assert.Equal(t, "2024-01-01", result[7].Format(DefaultTimeFormat))
+}
@@ -0,0 +1,183 @@
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Override is a holiday of an override file, which replaces the holidays of a provider with the same date and name
+// (e.g. to correct the regions of a regional holiday) or adds a holiday that the provider does not have
+type Override struct {
+	// Date is the date (YYYY-MM-DD) of the holiday, or its yearly date (MM-DD)
+	Date string `json:"date"`
+	Name string `json:"name"`
+	// Kind is "public", "observance" or "other", "public" if empty
+	Kind string `json:"kind,omitempty"`
+	// Regions contains the region codes (ISO 3166-2) where the holiday applies, every region if empty
+	Regions []string `json:"regions,omitempty"`
+}
+
+// overrideFile is the structure of an override file
+type overrideFile struct {
+	Holidays []*Override `json:"holidays"`
+}
+
+// OverrideProvider is a provider whose holidays are changed by overrides, keeping the nationwide holidays and
+// the regional holidays of Region
+type OverrideProvider struct {
+	Provider  HolidayProvider
+	Overrides []*Override
+	// Region is the region code (ISO 3166-2) whose regional holidays are kept, only nationwide holidays if empty
+	Region string
+}
+
+// LoadOverrides reads the holidays of an override file, e.g. {"holidays": [{"date": "11-15", "name": "St. Leopold's Day", "regions": ["AT-3", "AT-9"]}]}
+func LoadOverrides(filePath string) ([]*Override, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var file overrideFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	for _, o := range file.Holidays {
+		if err := o.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return file.Holidays, nil
+}
+
+// Validate checks if the date and kind of an override are valid
+func (o *Override) Validate() error {
+	if _, err := o.getKind(); err != nil {
+		return err
+	}
+
+	if _, err := time.Parse(DefaultTimeFormat, o.Date); err == nil {
+		return nil
+	}
+
+	// a leap day is a valid yearly date
+	if _, err := time.Parse(DefaultTimeFormat, "2024-"+o.Date); err != nil {
+		return fmt.Errorf("invalid date of %s: %s", o.Name, o.Date)
+	}
+
+	return nil
+}
+
+// GetHolidays returns the holidays of the provider from start to end (inclusive) with the overrides
+func (p *OverrideProvider) GetHolidays(start, end time.Time) ([]*Holiday, error) {
+	return p.GetHolidaysContext(context.Background(), start, end)
+}
+
+// GetHolidaysContext is GetHolidays with a context, passed to the provider if it is a ContextHolidayProvider
+func (p *OverrideProvider) GetHolidaysContext(ctx context.Context, start, end time.Time) ([]*Holiday, error) {
+	holidays, err := getHolidays(ctx, p.Provider, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err = ApplyOverrides(holidays, p.Overrides, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterRegion(holidays, p.Region), nil
+}
+
+// ApplyOverrides returns the holidays from start to end (inclusive), with those of the same date and name as
+// an override replaced by it, and the other overrides added. An override with several regions gives a holiday
+// for each region.
+func ApplyOverrides(holidays []*Holiday, overrides []*Override, start, end time.Time) ([]*Holiday, error) {
+	var added []*Holiday
+	replaced := map[string]bool{}
+	for _, o := range overrides {
+		kind, err := o.getKind()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range o.getDates(start, end) {
+			replaced[getHolidayKey(d, o.Name)] = true
+
+			if len(o.Regions) == 0 {
+				added = append(added, &Holiday{Date: d, Name: o.Name, Kind: kind})
+			}
+			for _, r := range o.Regions {
+				added = append(added, &Holiday{Date: d, Name: o.Name, Kind: kind, Region: strings.ToUpper(r)})
+			}
+		}
+	}
+
+	var result []*Holiday
+	for _, h := range holidays {
+		if !replaced[getHolidayKey(h.Date, h.Name)] {
+			result = append(result, h)
+		}
+	}
+
+	return append(result, added...), nil
+}
+
+// FilterRegion returns the nationwide holidays and the regional holidays of a region (ISO 3166-2),
+// or only the nationwide holidays if region is empty
+func FilterRegion(holidays []*Holiday, region string) []*Holiday {
+	var filtered []*Holiday
+	for _, h := range holidays {
+		if h.Region == "" || (region != "" && strings.EqualFold(h.Region, region)) {
+			filtered = append(filtered, h)
+		}
+	}
+
+	return filtered
+}
+
+// getKind returns the holiday kind of an override
+func (o *Override) getKind() (HolidayKind, error) {
+	switch strings.ToLower(o.Kind) {
+	case "", "public":
+		return KindPublic, nil
+	case "observance":
+		return KindObservance, nil
+	case "other":
+		return KindOther, nil
+	default:
+		return KindOther, fmt.Errorf("invalid kind of %s: %s", o.Name, o.Kind)
+	}
+}
+
+// getDates returns the dates of an override from start to end (inclusive), every year for a yearly date
+func (o *Override) getDates(start, end time.Time) []time.Time {
+	if d, err := time.Parse(DefaultTimeFormat, o.Date); err == nil {
+		if d.Before(start) || d.After(end) {
+			return nil
+		}
+		return []time.Time{d}
+	}
+
+	var dates []time.Time
+	for year := start.Year(); year <= end.Year(); year++ {
+		// yearly dates that do not exist in a year (e.g. 02-29) are skipped
+		d, err := time.Parse(DefaultTimeFormat, fmt.Sprintf("%d-%s", year, o.Date))
+		if err != nil || d.Before(start) || d.After(end) {
+			continue
+		}
+		dates = append(dates, d)
+	}
+
+	return dates
+}
+
+// getHolidayKey returns the date and lowercase name of a holiday, which identify it
+func getHolidayKey(date time.Time, name string) string {
+	return date.Format(DefaultTimeFormat) + " " + strings.ToLower(strings.TrimSpace(name))
+}
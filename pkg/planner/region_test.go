@@ -0,0 +1,129 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadOverrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected int
+		err      string
+	}{
+		{
+			name:     "successful",
+			content:  `{"holidays": [{"date": "11-15", "name": "St. Leopold's Day", "regions": ["AT-3", "AT-9"]}, {"date": "2024-02-29", "name": "Leap Day", "kind": "observance"}]}`,
+			expected: 2,
+		},
+		{name: "invalid date", content: `{"holidays": [{"date": "11/15", "name": "St. Leopold's Day"}]}`, err: "invalid date of St. Leopold's Day: 11/15"},
+		{name: "invalid kind", content: `{"holidays": [{"date": "11-15", "name": "St. Leopold's Day", "kind": "bank"}]}`, err: "invalid kind of St. Leopold's Day: bank"},
+		{name: "invalid JSON", content: `[`, err: "unexpected end of JSON input"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "overrides.json")
+			err := os.WriteFile(filePath, []byte(tc.content), 0o600)
+			assert.Nil(t, err)
+
+			overrides, err := LoadOverrides(filePath)
+			if tc.err != "" {
+				assert.Equal(t, tc.err, err.Error())
+				assert.Nil(t, overrides)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, len(overrides))
+		})
+	}
+
+	t.Run("file does not exist", func(t *testing.T) {
+		overrides, err := LoadOverrides(filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Nil(t, overrides)
+	})
+}
+
+func TestApplyOverrides(t *testing.T) {
+	start, end := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	holidays := []*Holiday{
+		{Date: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), Name: "All Saints' Day", Kind: KindPublic},
+		{Date: time.Date(2023, 11, 15, 0, 0, 0, 0, time.UTC), Name: "St. Leopold's Day", Kind: KindPublic},
+	}
+	overrides := []*Override{
+		{Date: "11-15", Name: "st. leopold's day", Kind: "observance", Regions: []string{"at-3", "AT-9"}},
+		{Date: "2024-03-19", Name: "St. Joseph's Day", Regions: []string{"AT-2"}},
+		{Date: "2025-01-01", Name: "New Year's Day"},
+	}
+
+	result, err := ApplyOverrides(holidays, overrides, start, end)
+	assert.Nil(t, err)
+
+	// the nationwide St. Leopold's Day is replaced by a regional one for each region and year
+	assert.Equal(t, 6, len(result))
+	assert.Equal(t, "All Saints' Day", result[0].Name)
+	assert.Equal(t, "2023-11-15", result[1].Date.Format(DefaultTimeFormat))
+	assert.Equal(t, "AT-3", result[1].Region)
+	assert.Equal(t, KindObservance, result[1].Kind)
+	assert.Equal(t, "AT-9", result[2].Region)
+	assert.Equal(t, "2024-11-15", result[3].Date.Format(DefaultTimeFormat))
+	assert.Equal(t, "St. Joseph's Day", result[5].Name)
+	assert.Equal(t, KindPublic, result[5].Kind)
+
+	_, err = ApplyOverrides(holidays, []*Override{{Date: "11-15", Name: "St. Leopold's Day", Kind: "bank"}}, start, end)
+	assert.Equal(t, "invalid kind of St. Leopold's Day: bank", err.Error())
+}
+
+func TestFilterRegion(t *testing.T) {
+	holidays := []*Holiday{
+		{Date: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), Name: "All Saints' Day"},
+		{Date: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC), Name: "St. Leopold's Day", Region: "AT-3"},
+		{Date: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC), Name: "St. Leopold's Day", Region: "AT-9"},
+	}
+
+	filtered := FilterRegion(holidays, "at-9")
+	assert.Equal(t, 2, len(filtered))
+	assert.Equal(t, "AT-9", filtered[1].Region)
+
+	filtered = FilterRegion(holidays, "")
+	assert.Equal(t, 1, len(filtered))
+	assert.Equal(t, "All Saints' Day", filtered[0].Name)
+}
+
+func TestOverrideProvider(t *testing.T) {
+	provider := &OverrideProvider{
+		Provider: &fakeContextProvider{fakeProvider: fakeProvider{holidays: []*Holiday{
+			{Date: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), Name: "All Saints' Day", Kind: KindPublic},
+			{Date: time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC), Name: "St. Martin's Day", Kind: KindPublic, Region: "AT-1"},
+		}}},
+		Overrides: []*Override{{Date: "11-15", Name: "St. Leopold's Day", Regions: []string{"AT-3", "AT-9"}}},
+		Region:    "AT-9",
+	}
+
+	vacations, suggestions, err := SuggestContext(context.Background(), provider, "2024-11-01", "2024-11-30", nil)
+	assert.Nil(t, err)
+
+	// St. Leopold's Day in Vienna makes a long weekend, St. Martin's Day of Burgenland is not a day off
+	assert.Equal(t, 2, len(vacations))
+	assert.Equal(t, "2024-11-01", vacations[0].Start.Format(DefaultTimeFormat))
+	assert.Equal(t, "2024-11-15", vacations[1].Start.Format(DefaultTimeFormat))
+	for _, s := range suggestions {
+		for _, h := range s.Holidays {
+			assert.NotEqual(t, "St. Martin's Day", h.Name)
+		}
+	}
+
+	provider.Provider = &fakeProvider{err: errors.New("unavailable")}
+	holidays, err := provider.GetHolidays(time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "unavailable", err.Error())
+	assert.Nil(t, holidays)
+}
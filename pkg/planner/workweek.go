@@ -0,0 +1,125 @@
+package planner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	DefaultWeekend = "sat,sun"
+
+	weekdays = map[string]time.Weekday{
+		"sun": time.Sunday, "sunday": time.Sunday,
+		"mon": time.Monday, "monday": time.Monday,
+		"tue": time.Tuesday, "tuesday": time.Tuesday,
+		"wed": time.Wednesday, "wednesday": time.Wednesday,
+		"thu": time.Thursday, "thursday": time.Thursday,
+		"fri": time.Friday, "friday": time.Friday,
+		"sat": time.Saturday, "saturday": time.Saturday,
+	}
+)
+
+// WorkWeek contains the non-working weekdays of a schedule. Weeks has one set of non-working weekdays per week
+// and repeats in cycles starting from the week of Anchor (e.g. alternating 9/80 schedules).
+type WorkWeek struct {
+	Weeks  []map[time.Weekday]bool
+	Anchor time.Time
+}
+
+// workWeekConfig is the structure of a work week configuration file
+type workWeekConfig struct {
+	Weekend string `json:"weekend"`
+	Anchor  string `json:"anchor,omitempty"`
+}
+
+// ParseWorkWeek returns a work week from a comma-separated list of non-working weekdays, by full or 3-letter name
+// (e.g. "fri,saturday"). Weeks of a cycle are separated by "|" (e.g. "sat,sun|fri,sat,sun") and start from the week
+// of anchor (YYYY-MM-DD), which is required for a cycle of several weeks.
+func ParseWorkWeek(weekend, anchor string) (*WorkWeek, error) {
+	workWeek := &WorkWeek{}
+	if anchor != "" {
+		a, err := time.Parse(DefaultTimeFormat, anchor)
+		if err != nil {
+			return nil, err
+		}
+		workWeek.Anchor = a
+	}
+
+	for _, week := range strings.Split(weekend, "|") {
+		days := map[time.Weekday]bool{}
+		for _, name := range strings.Split(week, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			day, ok := weekdays[name]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday: %s", name)
+			}
+			days[day] = true
+		}
+		workWeek.Weeks = append(workWeek.Weeks, days)
+	}
+
+	// without an anchor, which week of the cycle comes first would be arbitrary
+	if len(workWeek.Weeks) > 1 && anchor == "" {
+		return nil, errors.New("missing anchor of alternating weeks")
+	}
+
+	return workWeek, nil
+}
+
+// LoadWorkWeek returns a work week from a JSON configuration file (e.g. {"weekend": "fri,sat"})
+func LoadWorkWeek(filePath string) (*WorkWeek, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var config workWeekConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	return ParseWorkWeek(config.Weekend, config.Anchor)
+}
+
+// IsWorkingDay checks if a date is a working day in the schedule
+func (w *WorkWeek) IsWorkingDay(date time.Time) bool {
+	if len(w.Weeks) == 0 {
+		return true
+	}
+
+	return !w.Weeks[w.weekIndex(date)][date.Weekday()]
+}
+
+// weekIndex returns the position of a date's week in the cycle of weeks
+func (w *WorkWeek) weekIndex(date time.Time) int {
+	if len(w.Weeks) == 1 {
+		return 0
+	}
+
+	weeks := floorDiv(weekStart(date)-weekStart(w.Anchor), 7)
+	return int(((weeks % int64(len(w.Weeks))) + int64(len(w.Weeks))) % int64(len(w.Weeks)))
+}
+
+// weekStart returns the day number of the Monday of a date's week
+func weekStart(date time.Time) int64 {
+	day := floorDiv(date.Unix(), 24*60*60)
+	return day - int64((date.Weekday()+6)%7)
+}
+
+// floorDiv returns the quotient of a and b rounded down
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+
+	return q
+}
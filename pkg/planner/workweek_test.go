@@ -0,0 +1,115 @@
+package planner
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWorkWeek(t *testing.T) {
+	t.Run("invalid weekday", func(t *testing.T) {
+		workWeek, err := ParseWorkWeek("sat,sunday,funday", "")
+		assert.Equal(t, "invalid weekday: funday", err.Error())
+		assert.Nil(t, workWeek)
+	})
+
+	t.Run("weekday names that only start like one", func(t *testing.T) {
+		for _, name := range []string{"saturnday", "sunflower", "fr", "frid"} {
+			workWeek, err := ParseWorkWeek(name, "")
+			assert.Equal(t, "invalid weekday: "+name, err.Error())
+			assert.Nil(t, workWeek)
+		}
+	})
+
+	t.Run("missing anchor of alternating weeks", func(t *testing.T) {
+		workWeek, err := ParseWorkWeek("sat,sun|fri,sat,sun", "")
+		assert.Equal(t, "missing anchor of alternating weeks", err.Error())
+		assert.Nil(t, workWeek)
+	})
+
+	t.Run("invalid anchor", func(t *testing.T) {
+		workWeek, err := ParseWorkWeek("sat,sun", "2023/05/01")
+		assert.NotNil(t, err)
+		assert.Nil(t, workWeek)
+	})
+
+	t.Run("default weekend", func(t *testing.T) {
+		workWeek, err := ParseWorkWeek(DefaultWeekend, "")
+		assert.Nil(t, err)
+		assert.Equal(t, []map[time.Weekday]bool{{time.Saturday: true, time.Sunday: true}}, workWeek.Weeks)
+	})
+
+	t.Run("full weekday names", func(t *testing.T) {
+		workWeek, err := ParseWorkWeek("Friday, Saturday", "")
+		assert.Nil(t, err)
+		assert.Equal(t, []map[time.Weekday]bool{{time.Friday: true, time.Saturday: true}}, workWeek.Weeks)
+	})
+
+	t.Run("alternating weeks", func(t *testing.T) {
+		workWeek, err := ParseWorkWeek("sat,sun|fri,sat,sun", "2024-01-05")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(workWeek.Weeks))
+		assert.Equal(t, "2024-01-05", workWeek.Anchor.Format(DefaultTimeFormat))
+	})
+}
+
+func TestLoadWorkWeek(t *testing.T) {
+	t.Run("file does not exist", func(t *testing.T) {
+		workWeek, err := LoadWorkWeek(t.TempDir() + "/not-exist.json")
+		assert.NotNil(t, err)
+		assert.Nil(t, workWeek)
+	})
+
+	t.Run("error parsing JSON file", func(t *testing.T) {
+		filePath := t.TempDir() + "/workweek.json"
+		err := os.WriteFile(filePath, []byte(`invalid`), 0o600)
+		assert.Nil(t, err)
+
+		workWeek, err := LoadWorkWeek(filePath)
+		assert.NotNil(t, err)
+		assert.Nil(t, workWeek)
+	})
+
+	t.Run("successful", func(t *testing.T) {
+		filePath := t.TempDir() + "/workweek.json"
+		err := os.WriteFile(filePath, []byte(`{"weekend": "fri,sat"}`), 0o600)
+		assert.Nil(t, err)
+
+		workWeek, err := LoadWorkWeek(filePath)
+		assert.Nil(t, err)
+		assert.Equal(t, []map[time.Weekday]bool{{time.Friday: true, time.Saturday: true}}, workWeek.Weeks)
+	})
+}
+
+func TestIsWorkingDay(t *testing.T) {
+	workWeek, err := ParseWorkWeek("sat,sun|fri,sat,sun", "2024-01-10")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		date     string
+		expected bool
+	}{
+		{date: "2024-01-05", expected: false},
+		{date: "2024-01-06", expected: false},
+		{date: "2024-01-12", expected: true},
+		{date: "2024-01-19", expected: false},
+		{date: "2024-01-26", expected: true},
+		{date: "2024-02-02", expected: false},
+		{date: "2023-12-29", expected: true},
+		{date: "2023-12-22", expected: false},
+	}
+
+	for _, tt := range tests {
+		d, err := time.Parse(DefaultTimeFormat, tt.date)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.expected, workWeek.IsWorkingDay(d), tt.date)
+	}
+
+	t.Run("no weeks", func(t *testing.T) {
+		d, err := time.Parse(DefaultTimeFormat, "2024-01-06")
+		assert.Nil(t, err)
+		assert.True(t, (&WorkWeek{}).IsWorkingDay(d))
+	})
+}
@@ -0,0 +1,54 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+)
+
+// austria contains the holidays of the Arbeitsruhegesetz and the patron saint days of the federal states.
+// The patron saint days are not statutory, but they are days off at schools and public offices of their state,
+// so they are public holidays of the regions that are asked for.
+var austria = &Calendar{
+	Country: "AT",
+	Name:    "Austria",
+	Regions: map[string]string{
+		"AT-1": "Burgenland",
+		"AT-2": "Carinthia",
+		"AT-3": "Lower Austria",
+		"AT-4": "Upper Austria",
+		"AT-5": "Salzburg",
+		"AT-6": "Styria",
+		"AT-7": "Tyrol",
+		"AT-8": "Vorarlberg",
+		"AT-9": "Vienna",
+	},
+	Rules: []*Rule{
+		{Name: "New Year's Day", Kind: planner.KindPublic, Date: Fixed(time.January, 1)},
+		{Name: "Epiphany", Kind: planner.KindPublic, Date: Fixed(time.January, 6)},
+		{Name: "St. Joseph's Day", Kind: planner.KindPublic, Date: Fixed(time.March, 19), Regions: []string{"AT-2", "AT-6", "AT-7", "AT-8"}},
+		{Name: "Good Friday", Kind: planner.KindObservance, Date: Easter(-2)},
+		{Name: "Easter Sunday", Kind: planner.KindPublic, Date: Easter(0)},
+		{Name: "Easter Monday", Kind: planner.KindPublic, Date: Easter(1)},
+		{Name: "National Holiday", Kind: planner.KindPublic, Date: Fixed(time.May, 1)},
+		{Name: "St. Florian's Day", Kind: planner.KindPublic, Date: Fixed(time.May, 4), Regions: []string{"AT-4"}},
+		{Name: "Mother's Day", Kind: planner.KindObservance, Date: NthWeekday(2, time.Sunday, time.May)},
+		{Name: "Ascension Day", Kind: planner.KindPublic, Date: Easter(39)},
+		{Name: "Whit Sunday", Kind: planner.KindPublic, Date: Easter(49)},
+		{Name: "Whit Monday", Kind: planner.KindPublic, Date: Easter(50)},
+		{Name: "Corpus Christi", Kind: planner.KindPublic, Date: Easter(60)},
+		{Name: "Father's Day", Kind: planner.KindObservance, Date: NthWeekday(2, time.Sunday, time.June)},
+		{Name: "Assumption of Mary", Kind: planner.KindPublic, Date: Fixed(time.August, 15)},
+		{Name: "St. Rupert's Day", Kind: planner.KindPublic, Date: Fixed(time.September, 24), Regions: []string{"AT-5"}},
+		{Name: "Carinthian Plebiscite Day", Kind: planner.KindPublic, Date: Fixed(time.October, 10), Regions: []string{"AT-2"}},
+		{Name: "National Day", Kind: planner.KindPublic, Date: Fixed(time.October, 26)},
+		{Name: "All Saints' Day", Kind: planner.KindPublic, Date: Fixed(time.November, 1)},
+		{Name: "St. Martin's Day", Kind: planner.KindPublic, Date: Fixed(time.November, 11), Regions: []string{"AT-1"}},
+		{Name: "St. Leopold's Day", Kind: planner.KindPublic, Date: Fixed(time.November, 15), Regions: []string{"AT-3", "AT-9"}},
+		{Name: "Immaculate Conception", Kind: planner.KindPublic, Date: Fixed(time.December, 8)},
+		{Name: "Christmas Eve", Kind: planner.KindObservance, Date: Fixed(time.December, 24)},
+		{Name: "Christmas Day", Kind: planner.KindPublic, Date: Fixed(time.December, 25)},
+		{Name: "St. Stephen's Day", Kind: planner.KindPublic, Date: Fixed(time.December, 26)},
+		{Name: "New Year's Eve", Kind: planner.KindObservance, Date: Fixed(time.December, 31)},
+	},
+}
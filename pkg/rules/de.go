@@ -0,0 +1,62 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+)
+
+// germany contains the federal holidays and the public holidays of the federal states. Holidays that only apply
+// to some municipalities (e.g. Assumption Day in parts of Bavaria) are not included.
+var germany = &Calendar{
+	Country: "DE",
+	Name:    "Germany",
+	Regions: map[string]string{
+		"DE-BB": "Brandenburg",
+		"DE-BE": "Berlin",
+		"DE-BW": "Baden-Württemberg",
+		"DE-BY": "Bavaria",
+		"DE-HB": "Bremen",
+		"DE-HE": "Hesse",
+		"DE-HH": "Hamburg",
+		"DE-MV": "Mecklenburg-Vorpommern",
+		"DE-NI": "Lower Saxony",
+		"DE-NW": "North Rhine-Westphalia",
+		"DE-RP": "Rhineland-Palatinate",
+		"DE-SH": "Schleswig-Holstein",
+		"DE-SL": "Saarland",
+		"DE-SN": "Saxony",
+		"DE-ST": "Saxony-Anhalt",
+		"DE-TH": "Thuringia",
+	},
+	Rules: []*Rule{
+		{Name: "New Year's Day", Kind: planner.KindPublic, Date: Fixed(time.January, 1)},
+		{Name: "Epiphany", Kind: planner.KindPublic, Date: Fixed(time.January, 6), Regions: []string{"DE-BW", "DE-BY", "DE-ST"}},
+		{Name: "International Women's Day", Kind: planner.KindPublic, Date: Fixed(time.March, 8), Regions: []string{"DE-BE"}, FirstYear: 2019},
+		{Name: "International Women's Day", Kind: planner.KindPublic, Date: Fixed(time.March, 8), Regions: []string{"DE-MV"}, FirstYear: 2023},
+		{Name: "Good Friday", Kind: planner.KindPublic, Date: Easter(-2)},
+		{Name: "Easter Sunday", Kind: planner.KindPublic, Date: Easter(0), Regions: []string{"DE-BB"}},
+		{Name: "Easter Monday", Kind: planner.KindPublic, Date: Easter(1)},
+		{Name: "Labour Day", Kind: planner.KindPublic, Date: Fixed(time.May, 1)},
+		{Name: "Liberation Day", Kind: planner.KindPublic, Date: Fixed(time.May, 8), Regions: []string{"DE-BE"}, FirstYear: 2020, LastYear: 2020},
+		{Name: "Liberation Day", Kind: planner.KindPublic, Date: Fixed(time.May, 8), Regions: []string{"DE-BE"}, FirstYear: 2025, LastYear: 2025},
+		{Name: "Mother's Day", Kind: planner.KindObservance, Date: NthWeekday(2, time.Sunday, time.May)},
+		{Name: "Ascension Day", Kind: planner.KindPublic, Date: Easter(39)},
+		{Name: "Whit Sunday", Kind: planner.KindPublic, Date: Easter(49), Regions: []string{"DE-BB"}},
+		{Name: "Whit Monday", Kind: planner.KindPublic, Date: Easter(50)},
+		{Name: "Corpus Christi", Kind: planner.KindPublic, Date: Easter(60), Regions: []string{"DE-BW", "DE-BY", "DE-HE", "DE-NW", "DE-RP", "DE-SL"}},
+		{Name: "Assumption Day", Kind: planner.KindPublic, Date: Fixed(time.August, 15), Regions: []string{"DE-SL"}},
+		{Name: "World Children's Day", Kind: planner.KindPublic, Date: Fixed(time.September, 20), Regions: []string{"DE-TH"}, FirstYear: 2019},
+		{Name: "Day of German Unity", Kind: planner.KindPublic, Date: Fixed(time.October, 3), FirstYear: 1990},
+		{Name: "Reformation Day", Kind: planner.KindPublic, Date: Fixed(time.October, 31), Regions: []string{"DE-BB", "DE-MV", "DE-SN", "DE-ST", "DE-TH"}},
+		{Name: "Reformation Day", Kind: planner.KindPublic, Date: Fixed(time.October, 31), Regions: []string{"DE-HB", "DE-HH", "DE-NI", "DE-SH"}, FirstYear: 2018},
+		{Name: "Reformation Day", Kind: planner.KindPublic, Date: Fixed(time.October, 31), FirstYear: 2017, LastYear: 2017},
+		{Name: "All Saints' Day", Kind: planner.KindPublic, Date: Fixed(time.November, 1), Regions: []string{"DE-BW", "DE-BY", "DE-NW", "DE-RP", "DE-SL"}},
+		{Name: "Repentance and Prayer Day", Kind: planner.KindPublic, Date: WeekdayBefore(time.Wednesday, time.November, 23), LastYear: 1994},
+		{Name: "Repentance and Prayer Day", Kind: planner.KindPublic, Date: WeekdayBefore(time.Wednesday, time.November, 23), Regions: []string{"DE-SN"}, FirstYear: 1995},
+		{Name: "Christmas Eve", Kind: planner.KindObservance, Date: Fixed(time.December, 24)},
+		{Name: "Christmas Day", Kind: planner.KindPublic, Date: Fixed(time.December, 25)},
+		{Name: "Second Day of Christmas", Kind: planner.KindPublic, Date: Fixed(time.December, 26)},
+		{Name: "New Year's Eve", Kind: planner.KindObservance, Date: Fixed(time.December, 31)},
+	},
+}
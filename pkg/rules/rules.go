@@ -0,0 +1,279 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+)
+
+// calendars contains the built-in rule sets by country code (ISO 3166-1 alpha-2)
+var calendars = map[string]*Calendar{
+	austria.Country: austria,
+	germany.Country: germany,
+}
+
+// DateFunc returns the date of a holiday in a year
+type DateFunc func(year int) time.Time
+
+// Substitution is what happens to a holiday that falls on a weekend (Saturday or Sunday)
+type Substitution int
+
+const (
+	// SubstituteNone keeps the holiday on the weekend without a substitute day
+	SubstituteNone Substitution = iota
+	// SubstituteNextWorkingDay adds a substitute day on the next working day that is not a public holiday
+	SubstituteNextWorkingDay
+	// SubstituteNearestWorkingDay adds a substitute day on Friday for a Saturday and on Monday for a Sunday,
+	// or on the next working day if that is already a public holiday
+	SubstituteNearestWorkingDay
+)
+
+// Rule describes how to compute a holiday
+type Rule struct {
+	Name       string
+	Kind       planner.HolidayKind
+	Date       DateFunc
+	Substitute Substitution
+	// Regions contains the region codes (ISO 3166-2) where the holiday applies, every region if empty
+	Regions []string
+	// FirstYear and LastYear limit the years of the holiday, no limit if zero
+	FirstYear int
+	LastYear  int
+}
+
+// Calendar contains the holiday rules of a country
+type Calendar struct {
+	Country string
+	Name    string
+	// Regions contains the names of the regions of the country by region code (ISO 3166-2)
+	Regions map[string]string
+	Rules   []*Rule
+}
+
+// Provider gets the holidays of a country or region from the built-in rules, without network access
+type Provider struct {
+	Calendar *Calendar
+	Region   string
+}
+
+// NewProvider returns a provider of the holidays of a country or region (e.g. "AT", "DE-BY")
+func NewProvider(code string) (*Provider, error) {
+	calendar, region, err := GetCalendar(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{Calendar: calendar, Region: region}, nil
+}
+
+// GetHolidays returns the holidays of the country or region from start to end (inclusive)
+func (p *Provider) GetHolidays(start, end time.Time) ([]*planner.Holiday, error) {
+	return p.Calendar.GetHolidays(p.Region, start, end), nil
+}
+
+// GetHolidays returns the holidays of a country or region (e.g. "AT", "DE-BY") from start to end (inclusive)
+func GetHolidays(code string, start, end time.Time) ([]*planner.Holiday, error) {
+	calendar, region, err := GetCalendar(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return calendar.GetHolidays(region, start, end), nil
+}
+
+// GetCalendar returns the rule set of a country and the region code of a country or region code (e.g. "DE-BY")
+func GetCalendar(code string) (*Calendar, string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	country, _, _ := strings.Cut(code, "-")
+
+	calendar, ok := calendars[country]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown country: %s", country)
+	}
+
+	if code == country {
+		return calendar, "", nil
+	}
+
+	if _, ok := calendar.Regions[code]; !ok {
+		return nil, "", fmt.Errorf("unknown region: %s", code)
+	}
+
+	return calendar, code, nil
+}
+
+// GetHolidays returns the holidays of a region from start to end (inclusive), or only the nationwide holidays
+// if region is empty
+func (c *Calendar) GetHolidays(region string, start, end time.Time) []*planner.Holiday {
+	var holidays []*planner.Holiday
+	for year := start.Year(); year <= end.Year(); year++ {
+		for _, h := range c.getYearHolidays(region, year) {
+			if !h.Date.Before(start) && !h.Date.After(end) {
+				holidays = append(holidays, h)
+			}
+		}
+	}
+
+	return holidays
+}
+
+// getYearHolidays returns the holidays of a region in a year sorted by date, including substitute days
+func (c *Calendar) getYearHolidays(region string, year int) []*planner.Holiday {
+	var holidays []*planner.Holiday
+	var substituted []*Rule
+	seen := map[string]bool{}
+	for _, r := range c.Rules {
+		if !r.appliesTo(region, year) {
+			continue
+		}
+
+		date := r.Date(year)
+		key := date.Format(planner.DefaultTimeFormat) + " " + r.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		holidays = append(holidays, &planner.Holiday{Date: date, Name: r.Name, Kind: r.Kind, Region: r.getRegion(region)})
+		if r.Substitute != SubstituteNone && isWeekend(date) {
+			substituted = append(substituted, r)
+		}
+	}
+
+	sort.SliceStable(substituted, func(i, j int) bool {
+		return substituted[i].Date(year).Before(substituted[j].Date(year))
+	})
+
+	daysOff := map[time.Time]bool{}
+	for _, h := range holidays {
+		if h.Kind == planner.KindPublic {
+			daysOff[h.Date] = true
+		}
+	}
+
+	for _, r := range substituted {
+		date := r.getSubstituteDate(year, daysOff)
+		daysOff[date] = true
+		holidays = append(holidays, &planner.Holiday{Date: date, Name: r.Name + " (substitute day)", Kind: r.Kind, Region: r.getRegion(region)})
+	}
+
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+
+	return holidays
+}
+
+// appliesTo checks if a rule applies to a region in a year
+func (r *Rule) appliesTo(region string, year int) bool {
+	if (r.FirstYear != 0 && year < r.FirstYear) || (r.LastYear != 0 && year > r.LastYear) {
+		return false
+	}
+
+	if len(r.Regions) == 0 {
+		return true
+	}
+
+	for _, reg := range r.Regions {
+		if reg == region {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getRegion returns the region of a holiday of a rule, empty if it applies to every region
+func (r *Rule) getRegion(region string) string {
+	if len(r.Regions) == 0 {
+		return ""
+	}
+
+	return region
+}
+
+// getSubstituteDate returns the working day that replaces a holiday on a weekend
+func (r *Rule) getSubstituteDate(year int, daysOff map[time.Time]bool) time.Time {
+	date := r.Date(year)
+	if r.Substitute == SubstituteNearestWorkingDay {
+		nearest := date.AddDate(0, 0, 1)
+		if date.Weekday() == time.Saturday {
+			nearest = date.AddDate(0, 0, -1)
+		}
+
+		if !daysOff[nearest] {
+			return nearest
+		}
+	}
+
+	for date = date.AddDate(0, 0, 1); isWeekend(date) || daysOff[date]; date = date.AddDate(0, 0, 1) {
+	}
+
+	return date
+}
+
+// isWeekend checks if a date is a Saturday or Sunday
+func isWeekend(date time.Time) bool {
+	return date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+}
+
+// Fixed returns the same date every year
+func Fixed(month time.Month, day int) DateFunc {
+	return func(year int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Easter returns the date a number of days after (or before, if negative) Easter Sunday
+func Easter(offset int) DateFunc {
+	return func(year int) time.Time {
+		return GetEaster(year).AddDate(0, 0, offset)
+	}
+}
+
+// NthWeekday returns the nth weekday of a month (e.g. the second Sunday of May), counted from the end if n is negative
+func NthWeekday(n int, weekday time.Weekday, month time.Month) DateFunc {
+	return func(year int) time.Time {
+		if n < 0 {
+			last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+			days := (int(last.Weekday()) - int(weekday) + 7) % 7
+			return last.AddDate(0, 0, -days+(n+1)*7)
+		}
+
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		days := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, days+(n-1)*7)
+	}
+}
+
+// WeekdayBefore returns the last weekday before a date (e.g. the Wednesday before November 23)
+func WeekdayBefore(weekday time.Weekday, month time.Month, day int) DateFunc {
+	return func(year int) time.Time {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+		days := (int(date.Weekday()) - int(weekday) + 7) % 7
+		return date.AddDate(0, 0, -days)
+	}
+}
+
+// GetEaster returns the date of Easter Sunday in the Gregorian calendar (anonymous Gregorian computus)
+func GetEaster(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,233 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGetEaster(t *testing.T) {
+	expected := map[int]time.Time{
+		1961: date(1961, time.April, 2),
+		2000: date(2000, time.April, 23),
+		2008: date(2008, time.March, 23),
+		2019: date(2019, time.April, 21),
+		2024: date(2024, time.March, 31),
+		2025: date(2025, time.April, 20),
+		2038: date(2038, time.April, 25),
+	}
+
+	for year, easter := range expected {
+		assert.Equal(t, easter, GetEaster(year), year)
+	}
+}
+
+func TestDateFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		date     DateFunc
+		expected time.Time
+	}{
+		{name: "fixed", date: Fixed(time.October, 26), expected: date(2024, time.October, 26)},
+		{name: "before Easter", date: Easter(-2), expected: date(2024, time.March, 29)},
+		{name: "after Easter", date: Easter(60), expected: date(2024, time.May, 30)},
+		{name: "second Sunday", date: NthWeekday(2, time.Sunday, time.May), expected: date(2024, time.May, 12)},
+		{name: "first day of month", date: NthWeekday(1, time.Wednesday, time.May), expected: date(2024, time.May, 1)},
+		{name: "last Monday", date: NthWeekday(-1, time.Monday, time.May), expected: date(2024, time.May, 27)},
+		{name: "last day of month", date: NthWeekday(-1, time.Friday, time.May), expected: date(2024, time.May, 31)},
+		{name: "second to last Monday", date: NthWeekday(-2, time.Monday, time.May), expected: date(2024, time.May, 20)},
+		{name: "weekday before", date: WeekdayBefore(time.Wednesday, time.November, 23), expected: date(2024, time.November, 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.date(2024))
+		})
+	}
+
+	// the day itself is excluded
+	assert.Equal(t, date(2022, time.November, 16), WeekdayBefore(time.Wednesday, time.November, 23)(2022))
+}
+
+func TestGetCalendar(t *testing.T) {
+	tests := []struct {
+		code     string
+		country  string
+		region   string
+		expected string
+	}{
+		{code: "AT", country: "AT"},
+		{code: "de-by", country: "DE", region: "DE-BY"},
+		{code: "FR", expected: "unknown country: FR"},
+		{code: "DE-XX", expected: "unknown region: DE-XX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			calendar, region, err := GetCalendar(tt.code)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, err.Error())
+				assert.Nil(t, calendar)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.country, calendar.Country)
+			assert.Equal(t, tt.region, region)
+		})
+	}
+}
+
+func TestGetHolidays(t *testing.T) {
+	t.Run("austria", func(t *testing.T) {
+		holidays, err := GetHolidays("AT", date(2024, time.January, 1), date(2024, time.December, 31))
+		assert.Nil(t, err)
+
+		var public []string
+		for _, h := range holidays {
+			if h.Kind == planner.KindPublic {
+				public = append(public, h.Date.Format(planner.DefaultTimeFormat)+" "+h.Name)
+			}
+		}
+		assert.Equal(t, []string{
+			"2024-01-01 New Year's Day", "2024-01-06 Epiphany", "2024-03-31 Easter Sunday", "2024-04-01 Easter Monday",
+			"2024-05-01 National Holiday", "2024-05-09 Ascension Day", "2024-05-19 Whit Sunday", "2024-05-20 Whit Monday",
+			"2024-05-30 Corpus Christi", "2024-08-15 Assumption of Mary", "2024-10-26 National Day",
+			"2024-11-01 All Saints' Day", "2024-12-08 Immaculate Conception", "2024-12-25 Christmas Day",
+			"2024-12-26 St. Stephen's Day",
+		}, public)
+	})
+
+	t.Run("federal state", func(t *testing.T) {
+		start, end := date(2024, time.October, 1), date(2024, time.November, 30)
+		tests := map[string][]string{
+			"DE":    {"2024-10-03 Day of German Unity"},
+			"DE-BY": {"2024-10-03 Day of German Unity", "2024-11-01 All Saints' Day"},
+			"DE-SN": {"2024-10-03 Day of German Unity", "2024-10-31 Reformation Day", "2024-11-20 Repentance and Prayer Day"},
+		}
+
+		for code, expected := range tests {
+			holidays, err := GetHolidays(code, start, end)
+			assert.Nil(t, err)
+
+			var names []string
+			for _, h := range holidays {
+				names = append(names, h.Date.Format(planner.DefaultTimeFormat)+" "+h.Name)
+			}
+			assert.Equal(t, expected, names, code)
+		}
+	})
+
+	t.Run("year limits", func(t *testing.T) {
+		for year, count := range map[int]int{2016: 0, 2017: 1, 2018: 1} {
+			holidays, err := GetHolidays("DE-HH", date(year, time.October, 31), date(year, time.October, 31))
+			assert.Nil(t, err)
+			assert.Equal(t, count, len(holidays), year)
+		}
+	})
+
+	t.Run("unknown country", func(t *testing.T) {
+		holidays, err := GetHolidays("XX", date(2024, time.January, 1), date(2024, time.December, 31))
+		assert.Equal(t, "unknown country: XX", err.Error())
+		assert.Nil(t, holidays)
+	})
+}
+
+func TestSubstitution(t *testing.T) {
+	calendar := &Calendar{
+		Country: "XX",
+		Rules: []*Rule{
+			{Name: "Christmas Day", Kind: planner.KindPublic, Date: Fixed(time.December, 25), Substitute: SubstituteNextWorkingDay},
+			{Name: "Boxing Day", Kind: planner.KindPublic, Date: Fixed(time.December, 26), Substitute: SubstituteNextWorkingDay},
+			{Name: "Independence Day", Kind: planner.KindPublic, Date: Fixed(time.July, 4), Substitute: SubstituteNearestWorkingDay},
+			{Name: "Remembrance Day", Kind: planner.KindObservance, Date: Fixed(time.November, 11)},
+		},
+	}
+
+	tests := []struct {
+		year     int
+		expected []string
+	}{
+		{year: 2021, expected: []string{
+			"2021-07-04 Independence Day", "2021-07-05 Independence Day (substitute day)", "2021-11-11 Remembrance Day",
+			"2021-12-25 Christmas Day", "2021-12-26 Boxing Day",
+			"2021-12-27 Christmas Day (substitute day)", "2021-12-28 Boxing Day (substitute day)",
+		}},
+		{year: 2020, expected: []string{
+			"2020-07-03 Independence Day (substitute day)", "2020-07-04 Independence Day", "2020-11-11 Remembrance Day",
+			"2020-12-25 Christmas Day", "2020-12-26 Boxing Day", "2020-12-28 Boxing Day (substitute day)",
+		}},
+		{year: 2024, expected: []string{
+			"2024-07-04 Independence Day", "2024-11-11 Remembrance Day", "2024-12-25 Christmas Day", "2024-12-26 Boxing Day",
+		}},
+	}
+
+	for _, tt := range tests {
+		var names []string
+		for _, h := range calendar.GetHolidays("", date(tt.year, time.January, 1), date(tt.year, time.December, 31)) {
+			names = append(names, h.Date.Format(planner.DefaultTimeFormat)+" "+h.Name)
+		}
+		assert.Equal(t, tt.expected, names, tt.year)
+	}
+}
+
+func TestProvider(t *testing.T) {
+	t.Run("regional holidays", func(t *testing.T) {
+		provider, err := NewProvider("DE-BY")
+		assert.Nil(t, err)
+
+		holidays, err := provider.GetHolidays(date(2024, time.October, 1), date(2024, time.November, 30))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(holidays))
+		assert.Equal(t, "", holidays[0].Region)
+		assert.Equal(t, "DE-BY", holidays[1].Region)
+	})
+
+	t.Run("planning without credentials", func(t *testing.T) {
+		provider, err := NewProvider("AT")
+		assert.Nil(t, err)
+
+		vacations, suggestions, err := planner.Suggest(provider, "2024-05-01", "2024-06-02", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(vacations))
+		assert.Equal(t, "2024-05-18", vacations[0].Start.Format(planner.DefaultTimeFormat))
+		assert.Equal(t, 3, vacations[0].Count)
+
+		var leaveDates []string
+		for _, s := range suggestions {
+			for _, d := range s.LeaveDates {
+				leaveDates = append(leaveDates, d.Format(planner.DefaultTimeFormat))
+			}
+		}
+		assert.Contains(t, leaveDates, "2024-05-10")
+		assert.Contains(t, leaveDates, "2024-05-31")
+	})
+
+	t.Run("patron saint day of the federal state", func(t *testing.T) {
+		for code, expected := range map[string][]string{"AT": {"2024-11-01"}, "AT-9": {"2024-11-01", "2024-11-15"}} {
+			provider, err := NewProvider(code)
+			assert.Nil(t, err)
+
+			vacations, _, err := planner.Suggest(provider, "2024-11-01", "2024-11-30", nil)
+			assert.Nil(t, err)
+
+			var starts []string
+			for _, v := range vacations {
+				starts = append(starts, v.Start.Format(planner.DefaultTimeFormat))
+			}
+			assert.Equal(t, expected, starts, code)
+		}
+	})
+
+	t.Run("unknown region", func(t *testing.T) {
+		provider, err := NewProvider("AT-10")
+		assert.Equal(t, "unknown region: AT-10", err.Error())
+		assert.Nil(t, provider)
+	})
+}
@@ -0,0 +1,240 @@
+package suggestion
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/jvmistica/holiday-planner-go/pkg/trello"
+)
+
+// RollbackTimeout is the time limit of rolling back a failed Trello run
+var RollbackTimeout = time.Minute
+
+// TrelloSink creates a Trello board with a list of vacations without leaves, a list of suggestions,
+// a list of the optimal plan if there is one, and the workflow lists that cards are moved to by hand
+// (see trello.WorkflowLists). Cards are labeled with their days off per leave and their season or quarter.
+// The objects created on Trello are recorded in a run (see trello.Run), which is rolled back if publishing fails.
+// It needs TRELLO_API_KEY and TRELLO_API_TOKEN.
+type TrelloSink struct {
+	// BoardName is the name of the board, trello.DefaultBoardName if empty
+	BoardName string
+	// BoardID is the ID of an existing board to sync, instead of finding it by name
+	BoardID string
+	// Sync updates the existing board instead of creating a new one every run (see trello.SyncBoard)
+	Sync bool
+	// KeepPartial keeps what was created if publishing fails, so that the next run resumes on the same board
+	KeepPartial bool
+	// Client is the Trello client, trello.GetDefaultClient() if nil
+	Client *trello.Client
+	// Labels contains the thresholds and period of the cards' labels, DefaultLabelOptions() if nil
+	Labels *LabelOptions
+}
+
+// Write creates a board with the vacations, suggestions and optimal plan of a result, or syncs the existing board.
+// If the last run stopped halfway, its board is synced instead.
+func (t *TrelloSink) Write(result *Result) error {
+	return t.WriteContext(context.Background(), result)
+}
+
+// WriteContext is Write with a context that cancels the requests to Trello. The run is still rolled back
+// after ctx is done, within RollbackTimeout.
+func (t *TrelloSink) WriteContext(ctx context.Context, result *Result) error {
+	labels := newLabelSet(t.Labels)
+	if err := labels.options.Validate(); err != nil {
+		return err
+	}
+
+	client := t.Client
+	if client == nil {
+		client = trello.GetDefaultClient()
+	}
+
+	run, err := t.getRun()
+	if err != nil {
+		return err
+	}
+
+	if err := t.publish(ctx, client.WithRun(run), run, result, labels); err != nil {
+		t.rollback(ctx, client, run)
+		return err
+	}
+
+	if err := run.Complete(); err != nil {
+		return err
+	}
+	log.Printf("Completed Trello run %s, undo it with: undo %s", run.ID, run.ID)
+
+	return nil
+}
+
+// getRun returns the last run to resume if it stopped halfway on the sink's board, otherwise a new run
+func (t *TrelloSink) getRun() (*trello.Run, error) {
+	run, err := trello.GetIncompleteRun()
+	if err != nil {
+		return nil, err
+	}
+
+	if run != nil && run.BoardID != "" && (t.BoardID == "" || t.BoardID == run.BoardID) {
+		log.Printf("Resuming Trello run %s on board %s", run.ID, run.BoardID)
+		return run, nil
+	}
+
+	return trello.NewRun(), nil
+}
+
+// rollback removes the objects created by a failed run, unless they are kept to resume it
+func (t *TrelloSink) rollback(ctx context.Context, client *trello.Client, run *trello.Run) {
+	if t.KeepPartial {
+		log.Printf("Kept the objects created by Trello run %s, the next run resumes it", run.ID)
+		return
+	}
+
+	// the run is rolled back even if it failed because ctx is done
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), RollbackTimeout)
+	defer cancel()
+
+	if err := client.Undo(ctx, run); err != nil {
+		log.Printf("failed to roll back Trello run %s - %s", run.ID, err.Error())
+		return
+	}
+	log.Printf("Rolled back Trello run %s", run.ID)
+}
+
+// publish creates or syncs the board of a run. The board of a resumed run is synced, so that what was created is kept.
+func (t *TrelloSink) publish(ctx context.Context, client *trello.Client, run *trello.Run, result *Result, labels *labelSet) error {
+	boardName := t.BoardName
+	if boardName == "" {
+		boardName = trello.DefaultBoardName
+	}
+
+	if t.Sync || run.BoardID != "" {
+		boardID := t.BoardID
+		if boardID == "" {
+			boardID = run.BoardID
+		}
+
+		report, err := client.SyncBoard(ctx, boardID, boardName, getListPlans(result, labels))
+		if err != nil {
+			return err
+		}
+		log.Printf("Synced board %s: %d cards created, %d updated, %d archived, %d unchanged",
+			report.BoardID, report.Created, report.Updated, report.Archived, report.Unchanged)
+		return nil
+	}
+
+	board, err := client.CreateBoard(ctx, boardName)
+	if err != nil {
+		return err
+	}
+
+	if err := client.EnsureLabels(ctx, board.ID, labels.all()); err != nil {
+		return err
+	}
+
+	// create the lists from the first column, without the optimal plan if there is none
+	for i, l := range getListPlans(result, labels) {
+		if l.Name == trello.ListOptimalPlan && result.Plan == nil {
+			continue
+		}
+
+		list, err := client.CreateList(ctx, board.ID, l.Name, fmt.Sprint(i+1))
+		if err != nil {
+			return err
+		}
+
+		for _, c := range l.Cards {
+			if _, err := client.CreateCardPlan(ctx, list.ID, c); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getListPlans returns the lists and cards of a board with the vacations, suggestions and optimal plan of a result,
+// followed by the workflow lists. The optimal plan list has no cards without a plan, so that the cards of a previous plan are archived.
+func getListPlans(result *Result, labels *labelSet) []*trello.ListPlan {
+	vacations := &trello.ListPlan{Name: trello.ListVacationWithoutLeaves, Labels: labels.all()}
+	for _, i := range result.Vacations {
+		vacations.Cards = append(vacations.Cards, getVacationCard(i, labels))
+	}
+
+	suggestions := &trello.ListPlan{Name: trello.ListSuggestions, Labels: labels.all()}
+	for _, i := range result.Suggestions {
+		suggestions.Cards = append(suggestions.Cards, getSuggestionCard(i, labels))
+	}
+
+	plan := &trello.ListPlan{Name: trello.ListOptimalPlan, Labels: labels.all()}
+	if result.Plan != nil {
+		for _, i := range result.Plan.Windows {
+			plan.Cards = append(plan.Cards, getSuggestionCard(i, labels))
+		}
+	}
+
+	return append([]*trello.ListPlan{vacations, suggestions, plan}, trello.WorkflowLists()...)
+}
+
+// getVacationCard returns the card of a vacation without leaves, identified by its dates
+func getVacationCard(v *planner.Vacation, labels *labelSet) *trello.CardPlan {
+	key := fmt.Sprintf("%s - %s ", v.Start.Format(planner.DefaultTimeFormat), v.End.Format(planner.DefaultTimeFormat))
+	return &trello.CardPlan{
+		Key:    key,
+		Name:   fmt.Sprintf("%s-> %d days", key, v.Count),
+		Desc:   getCardDescription(v.Holidays, nil),
+		Start:  getCardDate(v.Start),
+		Due:    getCardDate(v.End),
+		Labels: labels.get(v.Count, 0, v.Start),
+	}
+}
+
+// getSuggestionCard returns the card of a suggestion, identified by its dates, with a checklist of the leaves to request
+func getSuggestionCard(s *planner.Suggestion, labels *labelSet) *trello.CardPlan {
+	key := fmt.Sprintf("%s - %s ", s.Start.Format(planner.DefaultTimeFormat), s.End.Format(planner.DefaultTimeFormat))
+
+	var leaveDates []string
+	for _, d := range s.LeaveDates {
+		leaveDates = append(leaveDates, d.Format(planner.DefaultTimeFormat))
+	}
+
+	return &trello.CardPlan{
+		Key:       key,
+		Name:      fmt.Sprintf("%s-> %d leaves / %d days", key, s.Leaves, s.Vacation),
+		Desc:      getCardDescription(s.Holidays, leaveDates),
+		Start:     getCardDate(s.Start),
+		Due:       getCardDate(s.End),
+		Checklist: leaveDates,
+		Labels:    labels.get(s.Vacation, s.Leaves, s.Start),
+	}
+}
+
+// getCardDescription returns the description of a card with the holidays of its vacation and the dates that need a leave
+func getCardDescription(holidays []*planner.Holiday, leaveDates []string) string {
+	var lines []string
+	if len(holidays) > 0 {
+		lines = append(lines, "Holidays:")
+		for _, h := range holidays {
+			lines = append(lines, fmt.Sprintf("- %s, %s: %s", h.Date.Weekday(), h.Date.Format(planner.DefaultTimeFormat), h.Name))
+		}
+	}
+
+	if len(leaveDates) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "Leave needed on: "+strings.Join(leaveDates, ", "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// getCardDate returns a date as the time of a card's start or due date, at noon UTC so that it shows on the same day
+// in most time zones
+func getCardDate(date time.Time) *time.Time {
+	d := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+	return &d
+}
@@ -0,0 +1,178 @@
+package suggestion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/jvmistica/holiday-planner-go/pkg/trello"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCards(t *testing.T) {
+	labels := newLabelSet(nil)
+	unityDay := &planner.Holiday{Date: time.Date(2024, time.October, 3, 0, 0, 0, 0, time.UTC), Name: "German Unity Day", Kind: planner.KindPublic}
+
+	t.Run("vacation", func(t *testing.T) {
+		card := getVacationCard(&planner.Vacation{
+			Start:    time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC),
+			End:      time.Date(2024, time.November, 3, 0, 0, 0, 0, time.UTC),
+			Count:    3,
+			Holidays: []*planner.Holiday{{Date: time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), Name: "All Saints' Day"}},
+		}, labels)
+		assert.Equal(t, "2024-11-01 - 2024-11-03 -> 3 days", card.Name)
+		assert.Equal(t, "Holidays:\n- Friday, 2024-11-01: All Saints' Day", card.Desc)
+		assert.Equal(t, time.Date(2024, time.November, 1, 12, 0, 0, 0, time.UTC), *card.Start)
+		assert.Equal(t, time.Date(2024, time.November, 3, 12, 0, 0, 0, time.UTC), *card.Due)
+		assert.Empty(t, card.Checklist)
+		assert.Equal(t, []*trello.Label{labels.free, labels.periods[3]}, card.Labels)
+	})
+
+	t.Run("suggestion", func(t *testing.T) {
+		card := getSuggestionCard(&planner.Suggestion{
+			Vacation:   4,
+			Leaves:     1,
+			Start:      time.Date(2024, time.October, 3, 0, 0, 0, 0, time.UTC),
+			End:        time.Date(2024, time.October, 6, 0, 0, 0, 0, time.UTC),
+			LeaveDates: []time.Time{time.Date(2024, time.October, 4, 0, 0, 0, 0, time.UTC)},
+			Holidays:   []*planner.Holiday{unityDay},
+		}, labels)
+		assert.Equal(t, "2024-10-03 - 2024-10-06 ", card.Key)
+		assert.Equal(t, "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days", card.Name)
+		assert.Equal(t, "Holidays:\n- Thursday, 2024-10-03: German Unity Day\n\nLeave needed on: 2024-10-04", card.Desc)
+		assert.Equal(t, []string{"2024-10-04"}, card.Checklist)
+		assert.Equal(t, []*trello.Label{labels.excellent, labels.periods[3]}, card.Labels)
+	})
+
+	t.Run("suggestion without holidays", func(t *testing.T) {
+		card := getSuggestionCard(&planner.Suggestion{
+			Vacation:   9,
+			Leaves:     5,
+			Start:      time.Date(2024, time.July, 6, 0, 0, 0, 0, time.UTC),
+			End:        time.Date(2024, time.July, 14, 0, 0, 0, 0, time.UTC),
+			LeaveDates: []time.Time{time.Date(2024, time.July, 8, 0, 0, 0, 0, time.UTC), time.Date(2024, time.July, 9, 0, 0, 0, 0, time.UTC)},
+		}, labels)
+		assert.Equal(t, "Leave needed on: 2024-07-08, 2024-07-09", card.Desc)
+		assert.Equal(t, []*trello.Label{labels.fair, labels.periods[2]}, card.Labels)
+	})
+
+	t.Run("suggestion without leaves", func(t *testing.T) {
+		card := getSuggestionCard(&planner.Suggestion{
+			Vacation: 4,
+			Start:    time.Date(2024, time.October, 3, 0, 0, 0, 0, time.UTC),
+			End:      time.Date(2024, time.October, 6, 0, 0, 0, 0, time.UTC),
+			Holidays: []*planner.Holiday{unityDay},
+		}, labels)
+		assert.Equal(t, "Holidays:\n- Thursday, 2024-10-03: German Unity Day", card.Desc)
+		assert.Empty(t, card.Checklist)
+	})
+
+	t.Run("lists", func(t *testing.T) {
+		lists := getListPlans(getTestResult(), labels)
+		assert.Equal(t, 6, len(lists))
+		assert.Equal(t, trello.ListVacationWithoutLeaves, lists[0].Name)
+		assert.Equal(t, trello.ListOptimalPlan, lists[2].Name)
+		assert.Equal(t, 1, len(lists[2].Cards))
+		assert.Equal(t, labels.all(), lists[1].Labels)
+		assert.Equal(t, trello.ListBooked, lists[5].Name)
+		assert.True(t, lists[5].Manual)
+
+		lists = getListPlans(&Result{}, labels)
+		assert.Empty(t, lists[2].Cards)
+	})
+}
+
+func TestTrelloSinkRuns(t *testing.T) {
+	var failCards bool
+	var cancelCards context.CancelFunc
+	var created, deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/cards" && cancelCards != nil:
+			cancelCards()
+			<-r.Context().Done()
+			return
+		case r.Method == http.MethodPost && r.URL.Path == "/cards" && failCards:
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+		case r.Method == http.MethodPost:
+			created = append(created, r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+			_, err := fmt.Fprintf(w, `{"id": "id%d"}`, len(created))
+			assert.Nil(t, err)
+			return
+		case r.Method == http.MethodGet && r.URL.Path == "/boards/id1":
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"id": "id1", "name": "Holidays"}`))
+			assert.Nil(t, err)
+			return
+		}
+		writeTrelloResponse(t, w, r)
+	}))
+	defer ts.Close()
+
+	t.Run("rollback", func(t *testing.T) {
+		setTrelloClient(t, ts.URL)
+		failCards, created, deleted = true, nil, nil
+
+		err := (&TrelloSink{}).Write(getTestResult())
+		assert.Equal(t, "failed to create card - status code: 429", err.Error())
+		assert.Equal(t, []string{"/boards/id1"}, deleted)
+
+		runs, err := trello.ListRuns()
+		assert.Nil(t, err)
+		assert.Empty(t, runs)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		setTrelloClient(t, ts.URL)
+		failCards, created, deleted = false, nil, nil
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancelCards = cancel
+		defer func() {
+			cancelCards = nil
+		}()
+
+		// the board is still rolled back after the context is done
+		err := (&TrelloSink{}).WriteContext(ctx, getTestResult())
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"/boards/id1"}, deleted)
+
+		runs, err := trello.ListRuns()
+		assert.Nil(t, err)
+		assert.Empty(t, runs)
+	})
+
+	t.Run("keep partial and resume", func(t *testing.T) {
+		setTrelloClient(t, ts.URL)
+		failCards, created, deleted = true, nil, nil
+
+		err := (&TrelloSink{KeepPartial: true}).Write(getTestResult())
+		assert.Equal(t, "failed to create card - status code: 429", err.Error())
+		assert.Empty(t, deleted)
+
+		run, err := trello.GetIncompleteRun()
+		assert.Nil(t, err)
+		assert.Equal(t, "id1", run.BoardID)
+
+		// the next run syncs the board of the stopped run instead of creating another one
+		failCards, created = false, nil
+		err = (&TrelloSink{}).Write(getTestResult())
+		assert.Nil(t, err)
+		assert.NotContains(t, created, "/boards/")
+		assert.Contains(t, created, "/cards")
+
+		runs, err := trello.ListRuns()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(runs))
+		assert.True(t, runs[0].Completed)
+	})
+}
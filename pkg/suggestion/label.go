@@ -0,0 +1,145 @@
+package suggestion
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/trello"
+)
+
+var (
+	LabelPeriodSeason  = "season"
+	LabelPeriodQuarter = "quarter"
+	LabelPeriodNone    = "none"
+
+	defaultExcellentRatio = 3.0
+	defaultGoodRatio      = 2.0
+)
+
+// LabelOptions contains the thresholds of the labels that rate the cards of a board by days off per leave,
+// and the period (season or quarter) that cards are labeled with by their start date
+type LabelOptions struct {
+	// ExcellentRatio is the least number of days off per leave of an "excellent" card (default 3)
+	ExcellentRatio float64
+	// GoodRatio is the least number of days off per leave of a "good" card (default 2), cards with less are "fair"
+	GoodRatio float64
+	// Period is LabelPeriodSeason (default), LabelPeriodQuarter or LabelPeriodNone
+	Period string
+}
+
+// DefaultLabelOptions returns the options that rate 3 or more days off per leave as excellent and 2 or more as good,
+// and label cards with their season
+func DefaultLabelOptions() *LabelOptions {
+	return &LabelOptions{
+		ExcellentRatio: defaultExcellentRatio,
+		GoodRatio:      defaultGoodRatio,
+		Period:         LabelPeriodSeason,
+	}
+}
+
+// Validate checks if the thresholds are positive and in order, and the period is known
+func (o *LabelOptions) Validate() error {
+	switch {
+	case o.GoodRatio <= 0:
+		return fmt.Errorf("invalid good ratio: %g", o.GoodRatio)
+	case o.ExcellentRatio <= o.GoodRatio:
+		return fmt.Errorf("invalid excellent ratio: %g", o.ExcellentRatio)
+	case o.Period != LabelPeriodSeason && o.Period != LabelPeriodQuarter && o.Period != LabelPeriodNone:
+		return fmt.Errorf("invalid label period: %s", o.Period)
+	}
+
+	return nil
+}
+
+// labelSet contains the labels of a board, which are shared by its cards so that they all get the IDs of the board's labels
+type labelSet struct {
+	options   *LabelOptions
+	free      *trello.Label
+	excellent *trello.Label
+	good      *trello.Label
+	fair      *trello.Label
+	periods   []*trello.Label
+}
+
+// newLabelSet returns the labels of a board for the options, which are DefaultLabelOptions if nil. The rating labels
+// are named after their thresholds, so they replace the board's rating labels of other thresholds by their prefix.
+func newLabelSet(o *LabelOptions) *labelSet {
+	if o == nil {
+		o = DefaultLabelOptions()
+	}
+
+	labels := &labelSet{
+		options:   o,
+		free:      &trello.Label{Name: "free: no leave needed", Color: "sky"},
+		excellent: &trello.Label{Name: fmt.Sprintf("excellent: ≥%g days off per leave", o.ExcellentRatio), Color: "green", Prefix: "excellent:"},
+		good:      &trello.Label{Name: fmt.Sprintf("good: ≥%g days off per leave", o.GoodRatio), Color: "lime", Prefix: "good:"},
+		fair:      &trello.Label{Name: fmt.Sprintf("fair: <%g days off per leave", o.GoodRatio), Color: "yellow", Prefix: "fair:"},
+	}
+
+	switch o.Period {
+	case LabelPeriodSeason:
+		labels.periods = []*trello.Label{
+			{Name: "winter", Color: "blue"},
+			{Name: "spring", Color: "pink"},
+			{Name: "summer", Color: "orange"},
+			{Name: "autumn", Color: "red"},
+		}
+	case LabelPeriodQuarter:
+		labels.periods = []*trello.Label{
+			{Name: "Q1", Color: "blue"},
+			{Name: "Q2", Color: "pink"},
+			{Name: "Q3", Color: "orange"},
+			{Name: "Q4", Color: "red"},
+		}
+	}
+
+	return labels
+}
+
+// all returns every label of the set, whether a card has it or not
+func (l *labelSet) all() []*trello.Label {
+	return append([]*trello.Label{l.free, l.excellent, l.good, l.fair}, l.periods...)
+}
+
+// get returns the labels of a card of days off that needs leaves and starts on a date
+func (l *labelSet) get(days, leaves int, start time.Time) []*trello.Label {
+	labels := []*trello.Label{l.getRating(days, leaves)}
+	if period := l.getPeriod(start); period != nil {
+		labels = append(labels, period)
+	}
+
+	return labels
+}
+
+// getRating returns the label of the days off per leave of a card
+func (l *labelSet) getRating(days, leaves int) *trello.Label {
+	if leaves == 0 {
+		return l.free
+	}
+
+	ratio := float64(days) / float64(leaves)
+	switch {
+	case ratio >= l.options.ExcellentRatio:
+		return l.excellent
+	case ratio >= l.options.GoodRatio:
+		return l.good
+	default:
+		return l.fair
+	}
+}
+
+// getPeriod returns the label of the season (meteorological, northern hemisphere) or quarter of a date,
+// nil if cards have no period label
+func (l *labelSet) getPeriod(date time.Time) *trello.Label {
+	if len(l.periods) == 0 {
+		return nil
+	}
+
+	month := int(date.Month()) - 1
+	if l.options.Period == LabelPeriodSeason {
+		// December is the first month of winter
+		month = (month + 1) % 12
+	}
+
+	return l.periods[month/3]
+}
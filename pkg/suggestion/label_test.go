@@ -0,0 +1,71 @@
+package suggestion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *LabelOptions
+		expected string
+	}{
+		{name: "default", options: DefaultLabelOptions()},
+		{name: "quarters", options: &LabelOptions{ExcellentRatio: 4, GoodRatio: 1.5, Period: LabelPeriodQuarter}},
+		{name: "no period", options: &LabelOptions{ExcellentRatio: 3, GoodRatio: 2, Period: LabelPeriodNone}},
+		{name: "invalid good ratio", options: &LabelOptions{ExcellentRatio: 3, Period: LabelPeriodSeason}, expected: "invalid good ratio: 0"},
+		{name: "excellent below good", options: &LabelOptions{ExcellentRatio: 2, GoodRatio: 2.5, Period: LabelPeriodSeason}, expected: "invalid excellent ratio: 2"},
+		{name: "invalid period", options: &LabelOptions{ExcellentRatio: 3, GoodRatio: 2, Period: "month"}, expected: "invalid label period: month"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.options.Validate()
+			if tc.expected == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tc.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestLabelSet(t *testing.T) {
+	t.Run("ratings", func(t *testing.T) {
+		labels := newLabelSet(nil)
+		assert.Equal(t, "excellent: ≥3 days off per leave", labels.excellent.Name)
+		assert.Equal(t, "fair: <2 days off per leave", labels.fair.Name)
+		assert.Equal(t, labels.free, labels.getRating(3, 0))
+		assert.Equal(t, labels.excellent, labels.getRating(9, 3))
+		assert.Equal(t, labels.good, labels.getRating(5, 2))
+		assert.Equal(t, labels.fair, labels.getRating(9, 5))
+	})
+
+	t.Run("custom thresholds", func(t *testing.T) {
+		labels := newLabelSet(&LabelOptions{ExcellentRatio: 4, GoodRatio: 1.5, Period: LabelPeriodNone})
+		assert.Equal(t, "good: ≥1.5 days off per leave", labels.good.Name)
+		assert.Equal(t, labels.good, labels.getRating(9, 3))
+		assert.Equal(t, labels.good, labels.getRating(9, 5))
+		assert.Equal(t, 1, len(labels.get(4, 1, time.Date(2024, time.October, 3, 0, 0, 0, 0, time.UTC))))
+		assert.Equal(t, 4, len(labels.all()))
+	})
+
+	t.Run("seasons", func(t *testing.T) {
+		labels := newLabelSet(nil)
+		assert.Equal(t, "winter", labels.getPeriod(time.Date(2024, time.December, 21, 0, 0, 0, 0, time.UTC)).Name)
+		assert.Equal(t, "winter", labels.getPeriod(time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)).Name)
+		assert.Equal(t, "spring", labels.getPeriod(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)).Name)
+		assert.Equal(t, "summer", labels.getPeriod(time.Date(2025, time.August, 31, 0, 0, 0, 0, time.UTC)).Name)
+		assert.Equal(t, "autumn", labels.getPeriod(time.Date(2025, time.November, 30, 0, 0, 0, 0, time.UTC)).Name)
+	})
+
+	t.Run("quarters", func(t *testing.T) {
+		labels := newLabelSet(&LabelOptions{ExcellentRatio: 3, GoodRatio: 2, Period: LabelPeriodQuarter})
+		assert.Equal(t, "Q1", labels.getPeriod(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)).Name)
+		assert.Equal(t, "Q2", labels.getPeriod(time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)).Name)
+		assert.Equal(t, "Q4", labels.getPeriod(time.Date(2024, time.December, 21, 0, 0, 0, 0, time.UTC)).Name)
+	})
+}
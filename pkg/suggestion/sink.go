@@ -0,0 +1,278 @@
+package suggestion
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/ics"
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/jvmistica/holiday-planner-go/pkg/trello"
+)
+
+var (
+	SinkTrello   = "trello"
+	SinkTable    = "table"
+	SinkJSON     = "json"
+	SinkCSV      = "csv"
+	SinkMarkdown = "markdown"
+	SinkICS      = "ics"
+
+	// sinkAliases contains the other names of sinks
+	sinkAliases = map[string]string{"md": SinkMarkdown}
+
+	rowVacation   = "vacation"
+	rowSuggestion = "suggestion"
+	rowPlan       = "plan"
+)
+
+// Result contains the computed vacations, suggestions and optimal plan (nil without a leave budget)
+type Result struct {
+	Start       string
+	End         string
+	Vacations   []*planner.Vacation
+	Suggestions []*planner.Suggestion
+	Plan        *planner.Plan
+}
+
+// Sink is an output of the computed plan (e.g. a Trello board, a file)
+type Sink interface {
+	Write(result *Result) error
+}
+
+// ContextSink is a Sink that stops writing (e.g. its requests) when a context is done
+type ContextSink interface {
+	Sink
+	WriteContext(ctx context.Context, result *Result) error
+}
+
+// GetSinkName returns the lowercase name of a sink, with an alias (e.g. "md") replaced by the name it stands for
+func GetSinkName(name string) string {
+	name = strings.ToLower(name)
+	if alias, ok := sinkAliases[name]; ok {
+		return alias
+	}
+
+	return name
+}
+
+// NewSink returns the sink of a name ("trello", "table", "json", "csv", "markdown" or "ics"),
+// writing to w unless it is the Trello sink
+func NewSink(name string, w io.Writer) (Sink, error) {
+	switch GetSinkName(name) {
+	case SinkTrello:
+		return &TrelloSink{}, nil
+	case SinkTable:
+		return &TableSink{W: w}, nil
+	case SinkJSON:
+		return &JSONSink{W: w}, nil
+	case SinkCSV:
+		return &CSVSink{W: w}, nil
+	case SinkMarkdown:
+		return &MarkdownSink{W: w}, nil
+	case SinkICS:
+		return &ICSSink{W: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output: %s", name)
+	}
+}
+
+// row is a vacation, suggestion or window of the optimal plan in a tabular output
+type row struct {
+	Type       string   `json:"type"`
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Days       int      `json:"days"`
+	Leaves     int      `json:"leaves"`
+	LeaveDates []string `json:"leaveDates"`
+}
+
+// getRows returns the vacations, suggestions and windows of the optimal plan of a result as rows
+func getRows(result *Result) []*row {
+	var rows []*row
+	for _, v := range result.Vacations {
+		rows = append(rows, &row{
+			Type:       rowVacation,
+			Start:      v.Start.Format(planner.DefaultTimeFormat),
+			End:        v.End.Format(planner.DefaultTimeFormat),
+			Days:       v.Count,
+			LeaveDates: []string{},
+		})
+	}
+
+	rows = append(rows, getSuggestionRows(rowSuggestion, result.Suggestions)...)
+	if result.Plan != nil {
+		rows = append(rows, getSuggestionRows(rowPlan, result.Plan.Windows)...)
+	}
+
+	return rows
+}
+
+// getSuggestionRows returns suggestions as rows of a type
+func getSuggestionRows(rowType string, suggestions []*planner.Suggestion) []*row {
+	var rows []*row
+	for _, s := range suggestions {
+		leaveDates := []string{}
+		for _, d := range s.LeaveDates {
+			leaveDates = append(leaveDates, d.Format(planner.DefaultTimeFormat))
+		}
+
+		rows = append(rows, &row{
+			Type:       rowType,
+			Start:      s.Start.Format(planner.DefaultTimeFormat),
+			End:        s.End.Format(planner.DefaultTimeFormat),
+			Days:       s.Vacation,
+			Leaves:     s.Leaves,
+			LeaveDates: leaveDates,
+		})
+	}
+
+	return rows
+}
+
+// TableSink writes the plan as an aligned text table (e.g. to the terminal)
+type TableSink struct {
+	W io.Writer
+}
+
+// Write writes a row for each vacation, suggestion and window of the optimal plan
+func (t *TableSink) Write(result *Result) error {
+	w := tabwriter.NewWriter(t.W, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tSTART\tEND\tDAYS\tLEAVES\tLEAVE DATES")
+	for _, r := range getRows(result) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n", r.Type, r.Start, r.End, r.Days, r.Leaves, strings.Join(r.LeaveDates, ","))
+	}
+
+	if result.Plan != nil {
+		fmt.Fprintf(w, "\nOptimal plan: %d days off, %d leaves spent, %d leaves remaining\n",
+			result.Plan.DaysOff, result.Plan.LeavesSpent, result.Plan.LeavesRemaining)
+	}
+
+	return w.Flush()
+}
+
+// jsonPlan is the structure of the optimal plan in the JSON output
+type jsonPlan struct {
+	DaysOff         int    `json:"daysOff"`
+	LeavesSpent     int    `json:"leavesSpent"`
+	LeavesRemaining int    `json:"leavesRemaining"`
+	Windows         []*row `json:"windows"`
+}
+
+// jsonResult is the structure of the JSON output
+type jsonResult struct {
+	Start       string    `json:"start"`
+	End         string    `json:"end"`
+	Vacations   []*row    `json:"vacations"`
+	Suggestions []*row    `json:"suggestions"`
+	Plan        *jsonPlan `json:"plan,omitempty"`
+}
+
+// JSONSink writes the plan as a JSON document
+type JSONSink struct {
+	W io.Writer
+}
+
+// Write writes the vacations, suggestions and optimal plan as JSON
+func (j *JSONSink) Write(result *Result) error {
+	output := &jsonResult{
+		Start:       result.Start,
+		End:         result.End,
+		Vacations:   []*row{},
+		Suggestions: []*row{},
+	}
+
+	for _, r := range getRows(result) {
+		switch r.Type {
+		case rowVacation:
+			output.Vacations = append(output.Vacations, r)
+		case rowSuggestion:
+			output.Suggestions = append(output.Suggestions, r)
+		}
+	}
+
+	if result.Plan != nil {
+		output.Plan = &jsonPlan{
+			DaysOff:         result.Plan.DaysOff,
+			LeavesSpent:     result.Plan.LeavesSpent,
+			LeavesRemaining: result.Plan.LeavesRemaining,
+			Windows:         getSuggestionRows(rowPlan, result.Plan.Windows),
+		}
+	}
+
+	enc := json.NewEncoder(j.W)
+	enc.SetIndent("", "    ")
+	return enc.Encode(output)
+}
+
+// CSVSink writes the plan as comma-separated values, with leave dates separated by spaces
+type CSVSink struct {
+	W io.Writer
+}
+
+// Write writes a record for each vacation, suggestion and window of the optimal plan
+func (c *CSVSink) Write(result *Result) error {
+	w := csv.NewWriter(c.W)
+	if err := w.Write([]string{"type", "start", "end", "days", "leaves", "leave_dates"}); err != nil {
+		return err
+	}
+
+	for _, r := range getRows(result) {
+		record := []string{r.Type, r.Start, r.End, strconv.Itoa(r.Days), strconv.Itoa(r.Leaves), strings.Join(r.LeaveDates, " ")}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+// MarkdownSink writes the plan as Markdown tables (e.g. for a wiki page or a pull request)
+type MarkdownSink struct {
+	W io.Writer
+}
+
+// Write writes a section with a table for the vacations, the suggestions and the optimal plan
+func (m *MarkdownSink) Write(result *Result) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: %s to %s\n", trello.DefaultBoardName, result.Start, result.End)
+
+	b.WriteString("\n## " + trello.ListVacationWithoutLeaves + "\n\n| Start | End | Days |\n| --- | --- | --- |\n")
+	for _, v := range result.Vacations {
+		fmt.Fprintf(&b, "| %s | %s | %d |\n", v.Start.Format(planner.DefaultTimeFormat), v.End.Format(planner.DefaultTimeFormat), v.Count)
+	}
+
+	writeMarkdownSuggestions(&b, trello.ListSuggestions, result.Suggestions)
+	if result.Plan != nil {
+		writeMarkdownSuggestions(&b, trello.ListOptimalPlan, result.Plan.Windows)
+		fmt.Fprintf(&b, "\n%d days off, %d leaves spent, %d leaves remaining\n",
+			result.Plan.DaysOff, result.Plan.LeavesSpent, result.Plan.LeavesRemaining)
+	}
+
+	_, err := io.WriteString(m.W, b.String())
+	return err
+}
+
+// writeMarkdownSuggestions writes a section with a table of suggestions
+func writeMarkdownSuggestions(b *strings.Builder, title string, suggestions []*planner.Suggestion) {
+	b.WriteString("\n## " + title + "\n\n| Start | End | Days | Leaves | Leave dates |\n| --- | --- | --- | --- | --- |\n")
+	for _, r := range getSuggestionRows(rowSuggestion, suggestions) {
+		fmt.Fprintf(b, "| %s | %s | %d | %d | %s |\n", r.Start, r.End, r.Days, r.Leaves, strings.Join(r.LeaveDates, ", "))
+	}
+}
+
+// ICSSink writes the vacations and suggestions as an iCalendar file, which calendar apps can import
+type ICSSink struct {
+	W io.Writer
+}
+
+// Write writes the vacations and suggestions as all-day events
+func (i *ICSSink) Write(result *Result) error {
+	return ics.Write(i.W, trello.DefaultBoardName, result.Vacations, result.Suggestions)
+}
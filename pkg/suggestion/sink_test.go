@@ -0,0 +1,154 @@
+package suggestion
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/ics"
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter is a writer that always fails
+type failingWriter struct{}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestNewSink(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Sink
+	}{
+		{name: "trello", expected: &TrelloSink{}},
+		{name: "table", expected: &TableSink{}},
+		{name: "JSON", expected: &JSONSink{}},
+		{name: "csv", expected: &CSVSink{}},
+		{name: "markdown", expected: &MarkdownSink{}},
+		{name: "md", expected: &MarkdownSink{}},
+		{name: "ics", expected: &ICSSink{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sink, err := NewSink(tc.name, nil)
+			assert.Nil(t, err)
+			assert.IsType(t, tc.expected, sink)
+		})
+	}
+
+	t.Run("unknown sink", func(t *testing.T) {
+		sink, err := NewSink("pdf", nil)
+		assert.Equal(t, "unknown output: pdf", err.Error())
+		assert.Nil(t, sink)
+	})
+}
+
+func TestGetSinkName(t *testing.T) {
+	assert.Equal(t, SinkMarkdown, GetSinkName("md"))
+	assert.Equal(t, SinkMarkdown, GetSinkName("Markdown"))
+	assert.Equal(t, SinkJSON, GetSinkName("JSON"))
+}
+
+func TestSinks(t *testing.T) {
+	result := getTestResult()
+
+	t.Run("table", func(t *testing.T) {
+		var b bytes.Buffer
+		err := (&TableSink{W: &b}).Write(result)
+		assert.Nil(t, err)
+
+		lines := strings.Split(b.String(), "\n")
+		assert.Equal(t, "TYPE        START       END         DAYS  LEAVES  LEAVE DATES", lines[0])
+		assert.Equal(t, "vacation    2024-11-01  2024-11-03  3     0       ", lines[1])
+		assert.Equal(t, "suggestion  2024-10-03  2024-10-06  4     1       2024-10-04", lines[2])
+		assert.Equal(t, "plan        2024-10-03  2024-10-06  4     1       2024-10-04", lines[3])
+		assert.Contains(t, b.String(), "Optimal plan: 4 days off, 1 leaves spent, 4 leaves remaining")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var b bytes.Buffer
+		err := (&JSONSink{W: &b}).Write(result)
+		assert.Nil(t, err)
+
+		var output jsonResult
+		err = json.Unmarshal(b.Bytes(), &output)
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-10-01", output.Start)
+		assert.Equal(t, 1, len(output.Vacations))
+		assert.Equal(t, &row{Type: "suggestion", Start: "2024-10-03", End: "2024-10-06", Days: 4, Leaves: 1, LeaveDates: []string{"2024-10-04"}}, output.Suggestions[0])
+		assert.Equal(t, 4, output.Plan.LeavesRemaining)
+		assert.Equal(t, 1, len(output.Plan.Windows))
+	})
+
+	t.Run("JSON without plan", func(t *testing.T) {
+		var b bytes.Buffer
+		err := (&JSONSink{W: &b}).Write(&Result{Start: "2024-10-01", End: "2024-11-30"})
+		assert.Nil(t, err)
+		assert.Contains(t, b.String(), `"vacations": []`)
+		assert.NotContains(t, b.String(), `"plan"`)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		var b bytes.Buffer
+		err := (&CSVSink{W: &b}).Write(result)
+		assert.Nil(t, err)
+		assert.Equal(t, "type,start,end,days,leaves,leave_dates\n"+
+			"vacation,2024-11-01,2024-11-03,3,0,\n"+
+			"suggestion,2024-10-03,2024-10-06,4,1,2024-10-04\n"+
+			"plan,2024-10-03,2024-10-06,4,1,2024-10-04\n", b.String())
+	})
+
+	t.Run("Markdown", func(t *testing.T) {
+		var b bytes.Buffer
+		err := (&MarkdownSink{W: &b}).Write(result)
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(b.String(), "# Holidays: 2024-10-01 to 2024-11-30\n"))
+		assert.Contains(t, b.String(), "| 2024-11-01 | 2024-11-03 | 3 |\n")
+		assert.Contains(t, b.String(), "| 2024-10-03 | 2024-10-06 | 4 | 1 | 2024-10-04 |\n")
+		assert.Contains(t, b.String(), "4 days off, 1 leaves spent, 4 leaves remaining")
+	})
+
+	t.Run("ICS", func(t *testing.T) {
+		var b bytes.Buffer
+		err := (&ICSSink{W: &b}).Write(result)
+		assert.Nil(t, err)
+
+		calendar, err := ics.Parse(&b)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(calendar.Events))
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		for _, sink := range []Sink{&TableSink{W: &failingWriter{}}, &JSONSink{W: &failingWriter{}}, &CSVSink{W: &failingWriter{}}, &MarkdownSink{W: &failingWriter{}}, &ICSSink{W: &failingWriter{}}} {
+			err := sink.Write(result)
+			assert.Equal(t, "write failed", err.Error())
+		}
+	})
+}
+
+// getTestResult returns a result with a vacation, a suggestion and an optimal plan with that suggestion
+func getTestResult() *Result {
+	suggestion := &planner.Suggestion{
+		Vacation:   4,
+		Leaves:     1,
+		Start:      time.Date(2024, time.October, 3, 0, 0, 0, 0, time.UTC),
+		End:        time.Date(2024, time.October, 6, 0, 0, 0, 0, time.UTC),
+		LeaveDates: []time.Time{time.Date(2024, time.October, 4, 0, 0, 0, 0, time.UTC)},
+	}
+
+	return &Result{
+		Start: "2024-10-01",
+		End:   "2024-11-30",
+		Vacations: []*planner.Vacation{
+			{Start: time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.November, 3, 0, 0, 0, 0, time.UTC), Count: 3},
+		},
+		Suggestions: []*planner.Suggestion{suggestion},
+		Plan:        &planner.Plan{Windows: []*planner.Suggestion{suggestion}, DaysOff: 4, LeavesSpent: 1, LeavesRemaining: 4},
+	}
+}
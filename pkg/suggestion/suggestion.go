@@ -1,49 +1,75 @@
 package suggestion
 
 import (
-	"fmt"
+	"context"
+	"log"
 
-	"github.com/jvmistica/holiday-planner-go/pkg/gcal"
-	"github.com/jvmistica/holiday-planner-go/pkg/trello"
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
 )
 
-// GenerateSuggestions queries Google Calendar for holidays and generates a trello.List of long weekends and suggested leaves on Trello
-func GenerateSuggestions(gcpAPIKey, start, end, calendarID string) error {
-	vacationWithoutLeaves, suggestions, err := gcal.GetCalendarEvents(gcpAPIKey, start, end, calendarID)
-	if err != nil {
-		return err
-	}
+// GenerateSuggestions gets holidays from a provider (e.g. gcal.Provider, rules.Provider) and writes the long weekends
+// and suggested leaves to each sink, or to a Trello board if there are none. Free time and suggestions follow opts
+// (see planner.Suggest). If budget is greater than zero, the combination of vacations that gives the most days off
+// for that many leaves, minus the taken leaves of opts, is added as the optimal plan.
+func GenerateSuggestions(provider planner.HolidayProvider, start, end string, opts *planner.Options, budget int, sinks ...Sink) error {
+	return GenerateSuggestionsContext(context.Background(), provider, start, end, opts, budget, sinks...)
+}
 
-	boardID, err := trello.CreateBoard(trello.DefaultBoardName)
+// GenerateSuggestionsContext is GenerateSuggestions with a context that cancels getting holidays and writing to
+// sinks that support it (see planner.ContextHolidayProvider and ContextSink). No sink is written after ctx is done.
+func GenerateSuggestionsContext(ctx context.Context, provider planner.HolidayProvider, start, end string, opts *planner.Options, budget int, sinks ...Sink) error {
+	vacationWithoutLeaves, suggestions, err := planner.SuggestContext(ctx, provider, start, end, opts)
 	if err != nil {
 		return err
 	}
 
-	// create vacation list on the first column
-	vacationListID, err := trello.CreateList(boardID, trello.ListVacationWithoutLeaves, "1")
-	if err != nil {
-		return err
+	result := &Result{
+		Start:       start,
+		End:         end,
+		Vacations:   vacationWithoutLeaves,
+		Suggestions: suggestions,
 	}
 
-	// create suggestion list on the second column
-	suggestionListID, err := trello.CreateList(boardID, trello.ListSuggestions, "2")
-	if err != nil {
-		return err
-	}
+	if budget > 0 {
+		if opts != nil && len(opts.TakenLeaves) > 0 {
+			taken, err := planner.CountTakenLeaves(opts.TakenLeaves, start, end)
+			if err != nil {
+				return err
+			}
+			budget = max(budget-taken, 0)
+			log.Printf("Booked leaves: %d taken, %d left to plan", taken, budget)
+		}
 
-	for _, i := range vacationWithoutLeaves {
-		name := fmt.Sprintf("%s - %s -> %d days", i.Start.Format(gcal.DefaultTimeFormat), i.End.Format(gcal.DefaultTimeFormat), i.Count)
-		if _, err := trello.CreateCard(vacationListID, name); err != nil {
+		plan, err := planner.Optimize(vacationWithoutLeaves, suggestions, budget)
+		if err != nil {
 			return err
 		}
+		log.Printf("Optimal plan: %d days off, %d leaves spent, %d leaves remaining", plan.DaysOff, plan.LeavesSpent, plan.LeavesRemaining)
+		result.Plan = plan
 	}
 
-	for _, i := range suggestions {
-		name := fmt.Sprintf("%s - %s -> %d leaves / %d days", i.Start.Format(gcal.DefaultTimeFormat), i.End.Format(gcal.DefaultTimeFormat), i.Leaves, i.Vacation)
-		if _, err := trello.CreateCard(suggestionListID, name); err != nil {
+	if len(sinks) == 0 {
+		sinks = []Sink{&TrelloSink{}}
+	}
+
+	for _, s := range sinks {
+		if err := writeSink(ctx, s, result); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// writeSink writes a result to a sink, with ctx if the sink supports it
+func writeSink(ctx context.Context, s Sink, result *Result) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if cs, ok := s.(ContextSink); ok {
+		return cs.WriteContext(ctx, result)
+	}
+
+	return s.Write(result)
+}
@@ -1,35 +1,57 @@
 package suggestion
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jvmistica/holiday-planner-go/pkg/gcal"
+	"github.com/jvmistica/holiday-planner-go/pkg/ics"
+	"github.com/jvmistica/holiday-planner-go/pkg/planner"
+	"github.com/jvmistica/holiday-planner-go/pkg/rules"
 	"github.com/jvmistica/holiday-planner-go/pkg/trello"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGenerateSuggestions(t *testing.T) {
-	t.Run("path error, file does not exist", func(t *testing.T) {
-		err := GenerateSuggestions("testKey", "2023-05-01", "2023-06-31", t.TempDir())
+	t.Run("path error, cache directory is a file", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "file")
+		err := os.WriteFile(filePath, []byte(``), 0o600)
+		assert.Nil(t, err)
+
+		origDir := gcal.CacheDir
+		gcal.CacheDir = filePath
+		defer func() {
+			gcal.CacheDir = origDir
+		}()
+
+		err = GenerateSuggestions(gcal.NewProvider("testKey", "test"), "2023-05-01", "2023-06-30", nil, 0)
 		assert.NotNil(t, err)
 	})
 
+	t.Run("invalid options", func(t *testing.T) {
+		opts := planner.DefaultOptions()
+		opts.MinBlockDays = 0
+		err := GenerateSuggestions(gcal.NewProvider("testKey", "test"), "2023-05-01", "2023-06-30", opts, 0)
+		assert.Equal(t, "invalid minimum block length: 0", err.Error())
+	})
+
 	t.Run("failed to create board", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := gcal.DefaultFilePath
-		gcal.DefaultFilePath = tmpDir + "%s"
+		origDir := gcal.CacheDir
+		gcal.CacheDir = tmpDir
 		defer func() {
-			gcal.DefaultFilePath = origDir
+			gcal.CacheDir = origDir
 		}()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
-
-		_, err = f.Write([]byte(`{
+		writeCache(t, tmpDir, `{
 			"summary": "Holidays in Austria",
 			"nextSyncToken": "CMDu0emHs_8CEAAYASCn_tSAAg==",
 			"items": [{
@@ -45,38 +67,28 @@ func TestGenerateSuggestions(t *testing.T) {
 				"start": {
 					"date": "2023-09-25"
 				}
-			}]}`))
-		assert.Nil(t, err)
+			}]}`)
 
-		// mock board creation response
+		// mock Trello responses
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusUnauthorized)
 		}))
 		defer ts.Close()
+		setTrelloClient(t, ts.URL)
 
-		origURL := trello.CreateBoardURL
-		trello.CreateBoardURL = ts.URL
-		defer func() {
-			trello.CreateBoardURL = origURL
-		}()
-
-		err = GenerateSuggestions("testKey", "2023-06-01", "2024-01-31", "test")
+		err := GenerateSuggestions(gcal.NewProvider("testKey", "test"), "2023-06-01", "2024-01-31", nil, 0)
 		assert.Equal(t, "failed to create board - status code: 401", err.Error())
 	})
 
 	t.Run("failed to create list", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := gcal.DefaultFilePath
-		gcal.DefaultFilePath = tmpDir + "%s"
+		origDir := gcal.CacheDir
+		gcal.CacheDir = tmpDir
 		defer func() {
-			gcal.DefaultFilePath = origDir
+			gcal.CacheDir = origDir
 		}()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
-
-		_, err = f.Write([]byte(`{
+		writeCache(t, tmpDir, `{
 			"summary": "Holidays in Austria",
 			"nextSyncToken": "CMDu0emHs_8CEAAYASCn_tSAAg==",
 			"items": [{
@@ -92,53 +104,32 @@ func TestGenerateSuggestions(t *testing.T) {
 				"start": {
 					"date": "2023-09-25"
 				}
-			}]}`))
-		assert.Nil(t, err)
-
-		// mock board creation response
-		ts1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			_, err := w.Write([]byte(`{"id": "abc123a36eaf8d75e160000f"}`))
-			assert.Nil(t, err)
-		}))
-		defer ts1.Close()
-
-		origURL1 := trello.CreateBoardURL
-		trello.CreateBoardURL = ts1.URL
-		defer func() {
-			trello.CreateBoardURL = origURL1
-		}()
+			}]}`)
 
-		// mock list creation response
-		ts2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusUnauthorized)
+		// mock Trello responses
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/lists") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			writeTrelloResponse(t, w, r)
 		}))
-		defer ts2.Close()
-
-		ts2.URL = ts2.URL + "/%s"
-		origURL2 := trello.CreateListURL
-		trello.CreateListURL = ts2.URL
-		defer func() {
-			trello.CreateListURL = origURL2
-		}()
+		defer ts.Close()
+		setTrelloClient(t, ts.URL)
 
-		err = GenerateSuggestions("testKey", "2023-06-01", "2024-01-31", "test")
+		err := GenerateSuggestions(gcal.NewProvider("testKey", "test"), "2023-06-01", "2024-01-31", nil, 0)
 		assert.Equal(t, "failed to create list - status code: 401", err.Error())
 	})
 
 	t.Run("failed to create card", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		origDir := gcal.DefaultFilePath
-		gcal.DefaultFilePath = tmpDir + "%s"
+		origDir := gcal.CacheDir
+		gcal.CacheDir = tmpDir
 		defer func() {
-			gcal.DefaultFilePath = origDir
+			gcal.CacheDir = origDir
 		}()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
-
-		_, err = f.Write([]byte(`{
+		writeCache(t, tmpDir, `{
 			"summary": "Holidays in Austria",
 			"nextSyncToken": "CMDu0emHs_8CEAAYASCn_tSAAg==",
 			"items": [{
@@ -154,116 +145,232 @@ func TestGenerateSuggestions(t *testing.T) {
 				"start": {
 					"date": "2023-09-25"
 				}
-			}]}`))
-		assert.Nil(t, err)
+			}]}`)
 
-		// mock board creation response
-		ts1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			_, err := w.Write([]byte(`{"id": "abc123a36eaf8d75e160000f"}`))
-			assert.Nil(t, err)
+		// mock Trello responses
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/cards" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			writeTrelloResponse(t, w, r)
 		}))
-		defer ts1.Close()
-
-		origURL1 := trello.CreateBoardURL
-		trello.CreateBoardURL = ts1.URL
-		defer func() {
-			trello.CreateBoardURL = origURL1
-		}()
+		defer ts.Close()
+		setTrelloClient(t, ts.URL)
 
-		// mock list creation response
-		ts2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			_, err := w.Write([]byte(`{"id": "abc123a36ech8d75e160000f"}`))
-			assert.Nil(t, err)
-		}))
-		defer ts2.Close()
+		opts := planner.DefaultOptions()
+		opts.Observances = []string{"Yom Kippur"}
+		err := GenerateSuggestions(gcal.NewProvider("testKey", "test"), "2023-06-01", "2024-01-31", opts, 0)
+		assert.Equal(t, "failed to create card - status code: 401", err.Error())
+	})
 
-		ts2.URL = ts2.URL + "/%s"
-		origURL2 := trello.CreateListURL
-		trello.CreateListURL = ts2.URL
+	t.Run("successful", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		origDir := gcal.CacheDir
+		gcal.CacheDir = tmpDir
 		defer func() {
-			trello.CreateListURL = origURL2
+			gcal.CacheDir = origDir
 		}()
 
-		// mock card creation response
-		ts3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusUnauthorized)
+		data, err := os.ReadFile("fixtures/test_gcal_response.json")
+		assert.Nil(t, err)
+		writeCache(t, tmpDir, string(data))
+
+		// mock Trello responses
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeTrelloResponse(t, w, r)
 		}))
-		defer ts3.Close()
+		defer ts.Close()
+		setTrelloClient(t, ts.URL)
 
-		origURL3 := trello.CreateCardURL
-		trello.CreateCardURL = ts3.URL
-		defer func() {
-			trello.CreateCardURL = origURL3
-		}()
+		err = GenerateSuggestions(gcal.NewProvider("testKey", "test"), "2023-06-01", "2024-01-31", nil, 0)
+		assert.Nil(t, err)
 
-		err = GenerateSuggestions("testKey", "2023-06-01", "2024-01-31", "test")
-		assert.Equal(t, "failed to create card - status code: 401", err.Error())
+		err = GenerateSuggestions(gcal.NewProvider("testKey", "test"), "2023-06-01", "2024-01-31", nil, 25)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGenerateSuggestionsOffline(t *testing.T) {
+	t.Run("unknown country", func(t *testing.T) {
+		provider, err := rules.NewProvider("XX")
+		assert.Equal(t, "unknown country: XX", err.Error())
+		assert.Nil(t, provider)
 	})
 
 	t.Run("successful", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		origDir := gcal.DefaultFilePath
-		gcal.DefaultFilePath = tmpDir + "%s"
-		defer func() {
-			gcal.DefaultFilePath = origDir
-		}()
+		var cards []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("idList") != "" {
+				cards = append(cards, r.URL.Query().Get("name"))
+			}
+			writeTrelloResponse(t, w, r)
+		}))
+		defer ts.Close()
 
-		f, err := os.Create(tmpDir + "test")
-		assert.Nil(t, err)
-		defer f.Close()
+		setTrelloClient(t, ts.URL)
 
-		data, err := os.ReadFile("fixtures/test_gcal_response.json")
+		provider, err := rules.NewProvider("DE-BY")
 		assert.Nil(t, err)
 
-		_, err = f.Write(data)
+		err = GenerateSuggestions(provider, "2024-10-01", "2024-11-30", nil, 0)
 		assert.Nil(t, err)
+		assert.Contains(t, cards, "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days")
+		assert.Contains(t, cards, "2024-11-01 - 2024-11-03 -> 3 days")
+	})
 
-		// mock board creation response
-		ts1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	t.Run("sync existing board", func(t *testing.T) {
+		var created, archived []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var response string
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/members/me/boards":
+				response = `[{"id": "board1", "name": "Holidays"}]`
+			case r.Method == http.MethodGet && r.URL.Path == "/boards/board1/lists":
+				response = `[{"id": "list1", "name": "Leave suggestions"}]`
+			case r.Method == http.MethodGet && r.URL.Path == "/lists/list1/cards":
+				response = `[{"id": "card1", "name": "2023-01-01 - 2023-01-06 -> 3 leaves / 6 days"}]`
+			case r.Method == http.MethodGet:
+				response = `[]`
+			case r.Method == http.MethodPut:
+				archived = append(archived, r.URL.Path)
+				response = `{"id": "card1"}`
+			default:
+				created = append(created, r.URL.Query().Get("name"))
+				response = `{"id": "abc123a36eaf8d75e160000f"}`
+			}
 			w.WriteHeader(http.StatusOK)
-			_, err := w.Write([]byte(`{"id": "abc123a36eaf8d75e160000f"}`))
+			_, err := w.Write([]byte(response))
 			assert.Nil(t, err)
 		}))
-		defer ts1.Close()
+		defer ts.Close()
 
-		origURL1 := trello.CreateBoardURL
-		trello.CreateBoardURL = ts1.URL
-		defer func() {
-			trello.CreateBoardURL = origURL1
-		}()
+		setTrelloClient(t, ts.URL)
 
-		// mock list creation response
-		ts2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			_, err := w.Write([]byte(`{"id": "abc123a36ech8d75e160000f"}`))
-			assert.Nil(t, err)
-		}))
-		defer ts2.Close()
+		provider, err := rules.NewProvider("DE-BY")
+		assert.Nil(t, err)
 
-		ts2.URL = ts2.URL + "/%s"
-		origURL2 := trello.CreateListURL
-		trello.CreateListURL = ts2.URL
-		defer func() {
-			trello.CreateListURL = origURL2
-		}()
+		err = GenerateSuggestions(provider, "2024-10-01", "2024-11-30", nil, 0, &TrelloSink{Sync: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"/cards/card1"}, archived)
+		assert.Contains(t, created, trello.ListVacationWithoutLeaves)
+		assert.NotContains(t, created, trello.ListSuggestions)
+		assert.NotContains(t, created, trello.ListOptimalPlan)
+		assert.Contains(t, created, "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days")
+		assert.Contains(t, created, "excellent: ≥3 days off per leave")
+		assert.Contains(t, created, "autumn")
+	})
 
-		// mock card creation response
-		ts3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			_, err := w.Write([]byte(`{"id": "abc123a36eaf8d78u160000f"}`))
-			assert.Nil(t, err)
-		}))
-		defer ts3.Close()
+	t.Run("invalid labels", func(t *testing.T) {
+		provider, err := rules.NewProvider("DE-BY")
+		assert.Nil(t, err)
 
-		origURL3 := trello.CreateCardURL
-		trello.CreateCardURL = ts3.URL
-		defer func() {
-			trello.CreateCardURL = origURL3
-		}()
+		err = GenerateSuggestions(provider, "2024-10-01", "2024-11-30", nil, 0, &TrelloSink{Labels: &LabelOptions{ExcellentRatio: 1, GoodRatio: 2}})
+		assert.Equal(t, "invalid excellent ratio: 1", err.Error())
+	})
+}
 
-		err = GenerateSuggestions("testKey", "2023-06-01", "2024-01-31", "test")
+func TestGenerateSuggestionsSinks(t *testing.T) {
+	provider, err := rules.NewProvider("DE-BY")
+	assert.Nil(t, err)
+
+	t.Run("invalid options", func(t *testing.T) {
+		opts := planner.DefaultOptions()
+		opts.MaxLeaves = -1
+
+		var b bytes.Buffer
+		err := GenerateSuggestions(provider, "2024-10-01", "2024-11-30", opts, 0, &ICSSink{W: &b})
+		assert.Equal(t, "invalid maximum leaves: -1", err.Error())
+		assert.Empty(t, b.String())
+	})
+
+	t.Run("multiple sinks", func(t *testing.T) {
+		var calendarBuf, csvBuf bytes.Buffer
+		err := GenerateSuggestions(provider, "2024-10-01", "2024-11-30", nil, 5, &ICSSink{W: &calendarBuf}, &CSVSink{W: &csvBuf})
 		assert.Nil(t, err)
+
+		calendar, err := ics.Parse(&calendarBuf)
+		assert.Nil(t, err)
+		assert.Equal(t, trello.DefaultBoardName, calendar.Name)
+		assert.Equal(t, "free-20241101-20241103@holiday-planner-go", calendar.Events[0].UID)
+		assert.Equal(t, "leave-20241003-20241006@holiday-planner-go", calendar.Events[1].UID)
+		assert.Equal(t, "Leave needed on: 2024-10-04", calendar.Events[1].Description)
+
+		assert.Contains(t, csvBuf.String(), "vacation,2024-11-01,2024-11-03,3,0,\n")
+		assert.Contains(t, csvBuf.String(), "plan,2024-10-03,2024-10-06,4,1,2024-10-04\n")
+	})
+
+	t.Run("taken leaves", func(t *testing.T) {
+		opts := planner.DefaultOptions()
+		opts.TakenLeaves = []time.Time{time.Date(2024, time.October, 4, 0, 0, 0, 0, time.UTC)}
+
+		var b bytes.Buffer
+		err := GenerateSuggestions(provider, "2024-10-01", "2024-11-30", opts, 1, &CSVSink{W: &b})
+		assert.Nil(t, err)
+		assert.NotContains(t, b.String(), "2024-10-03")
+		assert.Contains(t, b.String(), "plan,2024-11-01,2024-11-03,3,0,\n")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// the sinks after the one that was interrupted are not written
+		var b bytes.Buffer
+		err := GenerateSuggestionsContext(ctx, provider, "2024-10-01", "2024-11-30", nil, 0, &cancelingSink{cancel: cancel}, &CSVSink{W: &b})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, b.String())
+
+		err = GenerateSuggestionsContext(ctx, provider, "2024-10-01", "2024-11-30", nil, 0, &CSVSink{W: &b})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, b.String())
 	})
+
+	t.Run("sink error", func(t *testing.T) {
+		err := GenerateSuggestions(provider, "2024-10-01", "2024-11-30", nil, 0, &TableSink{W: &failingWriter{}})
+		assert.Equal(t, "write failed", err.Error())
+	})
+}
+
+// cancelingSink is a sink that cancels its context when it is written, as if the run was interrupted
+type cancelingSink struct {
+	cancel context.CancelFunc
+}
+
+func (c *cancelingSink) Write(result *Result) error {
+	c.cancel()
+	return nil
+}
+
+// writeTrelloResponse writes an empty list for a GET request (e.g. of the labels of a board), otherwise a created object
+func writeTrelloResponse(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	response := `{"id": "abc123a36eaf8d75e160000f"}`
+	if r.Method == http.MethodGet {
+		response = `[]`
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte(response))
+	assert.Nil(t, err)
+}
+
+// setTrelloClient points the Trello client of the sinks to a base URL, and saves their runs into a temporary directory,
+// until the test ends
+func setTrelloClient(t *testing.T, baseURL string) {
+	origClient := trello.DefaultClient
+	origDir := trello.RunsDir
+	trello.DefaultClient = trello.NewClient(trello.Config{BaseURL: baseURL, HTTPClient: http.DefaultClient})
+	trello.RunsDir = t.TempDir()
+	t.Cleanup(func() {
+		trello.DefaultClient = origClient
+		trello.RunsDir = origDir
+	})
+}
+
+// writeCache writes events into the cache of the "test" calendar as fetched from 2023-06-01 to 2024-01-31
+func writeCache(t *testing.T, dir, events string) {
+	entry := fmt.Sprintf(`{"calendarId": "test", "ranges": [{"start": "2023-06-01", "end": "2024-01-31", "fetchedAt": %q}], "events": %s}`,
+		time.Now().UTC().Format(time.RFC3339), events)
+	err := os.WriteFile(filepath.Join(dir, "test.json"), []byte(entry), 0o600)
+	assert.Nil(t, err)
 }
@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket that allows a number of requests per interval, in bursts of up to that number
+type Limiter struct {
+	mu       sync.Mutex
+	capacity float64
+	// rate is the number of tokens added per nanosecond
+	rate   float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewLimiter returns a full token bucket of n requests per interval
+func NewLimiter(n int, interval time.Duration) *Limiter {
+	return &Limiter{
+		capacity: float64(n),
+		rate:     float64(n) / float64(interval),
+		tokens:   float64(n),
+		now:      time.Now,
+	}
+}
+
+// reserve takes a token and returns how long to wait until it is available
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = min(l.capacity, l.tokens+float64(now.Sub(l.last))*l.rate)
+	}
+	l.last = now
+
+	// the token is taken even if it is not available yet, so that waiting requests keep their order
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate)
+}
+
+// cancel gives back a token that was reserved but not used
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.capacity, l.tokens+1)
+}
+
+// Wait blocks until a request is allowed, or returns the error of ctx if it is done first
+func (l *Limiter) Wait(ctx context.Context) error {
+	delay := l.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,58 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2024, time.October, 1, 8, 0, 0, 0, time.UTC)
+	l := NewLimiter(100, 10*time.Second)
+	l.now = func() time.Time { return now }
+
+	t.Run("burst", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, time.Duration(0), l.reserve())
+		}
+
+		// every request over the burst waits for its own token
+		assert.Equal(t, 100*time.Millisecond, l.reserve())
+		assert.Equal(t, 200*time.Millisecond, l.reserve())
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		now = now.Add(time.Second)
+		assert.Equal(t, time.Duration(0), l.reserve())
+
+		// the bucket holds at most the burst
+		now = now.Add(time.Hour)
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, time.Duration(0), l.reserve())
+		}
+		assert.Equal(t, 100*time.Millisecond, l.reserve())
+	})
+
+	t.Run("canceled wait", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := l.Wait(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// the token of the canceled wait is given back
+		assert.Equal(t, 200*time.Millisecond, l.reserve())
+	})
+}
+
+func TestLimiterWait(t *testing.T) {
+	l := NewLimiter(2, 50*time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, l.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
@@ -0,0 +1,220 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	defaultMaxRetries = 3
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+	defaultTimeout    = 30 * time.Second
+)
+
+// Config contains the settings of a Transport. Zero values disable the setting (e.g. no rate limit, no retries).
+type Config struct {
+	// Rate is the number of requests allowed per Interval, in bursts of up to Rate, no limit if zero
+	Rate     int
+	Interval time.Duration
+	// MaxRetries is the number of times a request is retried after a 429, a 5xx or a network error. Requests
+	// with other methods than idempotent ones are only retried after a 429 or a 503 with a Retry-After header.
+	MaxRetries int
+	// MinBackoff is the delay before the first retry, which doubles with every retry up to MaxBackoff
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Timeout is the time limit of each attempt of a request, until its response body is read
+	Timeout time.Duration
+	// Base is the transport that sends the requests, http.DefaultTransport if nil
+	Base http.RoundTripper
+}
+
+// DefaultConfig returns the settings that retry a request up to 3 times, waiting from 500ms to 30s in between,
+// and limit each attempt to 30s, without a rate limit
+func DefaultConfig() Config {
+	return Config{
+		MaxRetries: defaultMaxRetries,
+		MinBackoff: defaultMinBackoff,
+		MaxBackoff: defaultMaxBackoff,
+		Timeout:    defaultTimeout,
+	}
+}
+
+// Transport is an http.RoundTripper that limits the rate of requests, and retries requests that failed with
+// a 429 (Too Many Requests), a 5xx or a network error, with exponential backoff and jitter or after the
+// delay of their Retry-After header. Non-idempotent requests (e.g. POST) are only retried when the server
+// tells that it did not handle them.
+type Transport struct {
+	config  Config
+	limiter *Limiter
+}
+
+// New returns a transport with a configuration
+func New(config Config) *Transport {
+	t := &Transport{config: config}
+	if t.config.Base == nil {
+		t.config.Base = http.DefaultTransport
+	}
+
+	if config.Rate > 0 && config.Interval > 0 {
+		t.limiter = NewLimiter(config.Rate, config.Interval)
+	}
+
+	return t
+}
+
+// NewClient returns an HTTP client that sends its requests through a transport with a configuration
+func NewClient(config Config) *http.Client {
+	return &http.Client{Transport: New(config)}
+}
+
+// RoundTrip sends a request, waiting for the rate limit and retrying it if it failed temporarily.
+// The response of the last attempt is returned.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if t.limiter != nil {
+			if err := t.limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := t.send(req, attempt)
+		if attempt >= t.config.MaxRetries || !isRetryable(req, res, err) {
+			return res, err
+		}
+
+		delay := t.getBackoff(attempt)
+		if res != nil {
+			delay = max(delay, getRetryAfter(res))
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send sends an attempt of a request with the time limit of the transport, which ends when the response body is closed
+func (t *Transport) send(req *http.Request, attempt int) (*http.Response, error) {
+	if attempt > 0 && req.Body != nil {
+		if req.GetBody == nil {
+			return nil, errors.New("request body cannot be sent again")
+		}
+
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	if t.config.Timeout <= 0 {
+		return t.config.Base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.config.Timeout)
+	res, err := t.config.Base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+
+	return res, nil
+}
+
+// getBackoff returns the delay before a retry, which doubles with every attempt up to MaxBackoff,
+// with a random jitter of up to half of it so that clients that failed together do not retry together
+func (t *Transport) getBackoff(attempt int) time.Duration {
+	backoff := t.config.MinBackoff << attempt
+	if backoff <= 0 || (t.config.MaxBackoff > 0 && backoff > t.config.MaxBackoff) {
+		backoff = t.config.MaxBackoff
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// isRetryable checks if an attempt of a request failed temporarily. Network errors and most 5xx are only
+// retried for idempotent methods, since the request may have been handled (e.g. a 502 after a card was created).
+// A 429 or a 503 with a Retry-After header means that it was not.
+func isRetryable(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return req.Context().Err() == nil && errors.As(err, &netErr) && isIdempotent(req.Method)
+	}
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return true
+	case res.StatusCode == http.StatusServiceUnavailable && res.Header.Get("Retry-After") != "":
+		return true
+	default:
+		return res.StatusCode >= http.StatusInternalServerError && isIdempotent(req.Method)
+	}
+}
+
+// isIdempotent checks if sending a request with a method more than once has the same effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// getRetryAfter returns the delay of the Retry-After header of a response, in seconds or as a date, or zero if it has none
+func getRetryAfter(res *http.Response) time.Duration {
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
+
+// sleep waits for a delay, or returns the error of ctx if it is done first
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelBody is a response body that ends the time limit of its request when it is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
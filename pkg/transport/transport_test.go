@@ -0,0 +1,228 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testConfig returns settings that retry quickly
+func testConfig() Config {
+	return Config{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond, Timeout: time.Second}
+}
+
+func TestTransport(t *testing.T) {
+	var attempts int
+	var statuses []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		if attempts < len(statuses) {
+			status = statuses[attempts]
+		}
+		attempts++
+
+		if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(status)
+		_, err := io.WriteString(w, r.Method)
+		assert.Nil(t, err)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name             string
+		method           string
+		statuses         []int
+		expectedStatus   int
+		expectedAttempts int
+	}{
+		{name: "successful", method: http.MethodGet, expectedStatus: http.StatusOK, expectedAttempts: 1},
+		{
+			name:             "retried after too many requests and server errors",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 4,
+		},
+		{
+			name:             "POST retried after too many requests and unavailable with Retry-After",
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "POST is not retried after a server error",
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusInternalServerError},
+			expectedStatus:   http.StatusInternalServerError,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "POST is not retried after a bad gateway",
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusBadGateway},
+			expectedStatus:   http.StatusBadGateway,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "retries exhausted",
+			method:           http.MethodGet,
+			statuses:         []int{500, 500, 500, 500, 500},
+			expectedStatus:   http.StatusInternalServerError,
+			expectedAttempts: 4,
+		},
+		{
+			name:             "client error is not retried",
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusUnauthorized},
+			expectedStatus:   http.StatusUnauthorized,
+			expectedAttempts: 1,
+		},
+	}
+
+	client := NewClient(testConfig())
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts, statuses = 0, tc.statuses
+
+			req, err := http.NewRequest(tc.method, ts.URL, strings.NewReader("body"))
+			assert.Nil(t, err)
+
+			res, err := client.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(res.Body)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.method, string(body))
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			assert.Equal(t, tc.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestTransportErrors(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		var attempts int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			<-r.Context().Done()
+		}))
+		defer ts.Close()
+
+		config := testConfig()
+		config.Timeout = 10 * time.Millisecond
+		client := NewClient(config)
+
+		// a timed out POST may have been handled, so it is not sent again
+		res, err := client.Post(ts.URL, "text/plain", nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, res)
+		assert.Equal(t, 1, attempts)
+
+		res, err = client.Get(ts.URL)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, res)
+		assert.Equal(t, 5, attempts)
+	})
+
+	t.Run("canceled during backoff", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer ts.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+		assert.Nil(t, err)
+
+		res, err := NewClient(testConfig()).Do(req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, res)
+	})
+
+	t.Run("invalid URL is not retried", func(t *testing.T) {
+		res, err := NewClient(testConfig()).Get("/not/exist")
+		assert.Contains(t, err.Error(), "unsupported protocol scheme")
+		assert.Nil(t, res)
+	})
+}
+
+func TestTransportRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	config := testConfig()
+	config.Rate, config.Interval = 2, 50*time.Millisecond
+	client := NewClient(config)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		res, err := client.Get(ts.URL)
+		assert.Nil(t, err)
+		res.Body.Close()
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestGetBackoff(t *testing.T) {
+	tr := New(Config{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+
+	for attempt, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		backoff := tr.getBackoff(attempt)
+		assert.GreaterOrEqual(t, backoff, expected/2)
+		assert.LessOrEqual(t, backoff, expected)
+	}
+
+	assert.Equal(t, time.Duration(0), New(Config{}).getBackoff(2))
+}
+
+func TestGetRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "none", value: "", expected: 0},
+		{name: "seconds", value: "10", expected: 10 * time.Second},
+		{name: "past date", value: "Wed, 21 Oct 2015 07:28:00 GMT", expected: 0},
+		{name: "invalid", value: "soon", expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res := &http.Response{Header: http.Header{}}
+			res.Header.Set("Retry-After", tc.value)
+			assert.Equal(t, tc.expected, getRetryAfter(res))
+		})
+	}
+
+	t.Run("future date", func(t *testing.T) {
+		res := &http.Response{Header: http.Header{}}
+		res.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+		delay := getRetryAfter(res)
+		assert.Greater(t, delay, 58*time.Second)
+		assert.LessOrEqual(t, delay, time.Minute)
+	})
+}
+
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
+	assert.Equal(t, 3, config.MaxRetries)
+	assert.Equal(t, 30*time.Second, config.Timeout)
+	assert.Nil(t, New(config).limiter)
+	assert.Equal(t, http.DefaultTransport, New(config).config.Base)
+}
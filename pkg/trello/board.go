@@ -0,0 +1,253 @@
+package trello
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	boardFields = "name,closed"
+	listFields  = "name,closed,pos"
+	cardFields  = "name,desc,idList,idLabels,closed,pos,start,due"
+)
+
+// Board is a Trello board
+type Board struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+}
+
+// List is a list of a Trello board
+type List struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+// Card is a card of a Trello list
+type Card struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Desc     string   `json:"desc"`
+	IDList   string   `json:"idList"`
+	IDLabels []string `json:"idLabels"`
+	Closed   bool     `json:"closed"`
+	Pos      float64  `json:"pos"`
+	// Start and Due are the start and due dates of the card, nil if it has none
+	Start *time.Time `json:"start"`
+	Due   *time.Time `json:"due"`
+}
+
+// GetBoards returns the open boards of the user
+func (c *Client) GetBoards(ctx context.Context) ([]*Board, error) {
+	params := url.Values{}
+	params.Add("filter", "open")
+	params.Add("fields", boardFields)
+
+	var boards []*Board
+	if err := c.sendRequest(ctx, http.MethodGet, "/members/me/boards", params, "get boards", &boards); err != nil {
+		return nil, err
+	}
+
+	return boards, nil
+}
+
+// GetBoard returns a board by ID
+func (c *Client) GetBoard(ctx context.Context, boardID string) (*Board, error) {
+	params := url.Values{}
+	params.Add("fields", boardFields)
+
+	var board *Board
+	if err := c.sendRequest(ctx, http.MethodGet, "/boards/"+url.PathEscape(boardID), params, "get board", &board); err != nil {
+		return nil, err
+	}
+
+	return board, nil
+}
+
+// CreateBoard creates a board
+func (c *Client) CreateBoard(ctx context.Context, boardName string) (*Board, error) {
+	params := url.Values{}
+	params.Add("name", boardName)
+	params.Add("prefs_background", defaultBoardBackground)
+
+	var board *Board
+	if err := c.sendRequest(ctx, http.MethodPost, "/boards/", params, "create board", &board); err != nil {
+		return nil, err
+	}
+
+	if err := c.record(ObjectBoard, board.ID, board.Name); err != nil {
+		return nil, err
+	}
+
+	return board, nil
+}
+
+// UpdateBoard renames a board
+func (c *Client) UpdateBoard(ctx context.Context, boardID, boardName string) (*Board, error) {
+	params := url.Values{}
+	params.Add("name", boardName)
+
+	var board *Board
+	if err := c.sendRequest(ctx, http.MethodPut, "/boards/"+url.PathEscape(boardID), params, "update board", &board); err != nil {
+		return nil, err
+	}
+
+	return board, nil
+}
+
+// ArchiveBoard closes a board, which can still be reopened on Trello
+func (c *Client) ArchiveBoard(ctx context.Context, boardID string) error {
+	params := url.Values{}
+	params.Add("closed", "true")
+
+	return c.sendRequest(ctx, http.MethodPut, "/boards/"+url.PathEscape(boardID), params, "archive board", nil)
+}
+
+// DeleteBoard deletes a board permanently
+func (c *Client) DeleteBoard(ctx context.Context, boardID string) error {
+	return c.sendRequest(ctx, http.MethodDelete, "/boards/"+url.PathEscape(boardID), nil, "delete board", nil)
+}
+
+// GetLists returns the open lists of a board, in order
+func (c *Client) GetLists(ctx context.Context, boardID string) ([]*List, error) {
+	params := url.Values{}
+	params.Add("filter", "open")
+	params.Add("fields", listFields)
+
+	var lists []*List
+	if err := c.sendRequest(ctx, http.MethodGet, "/boards/"+url.PathEscape(boardID)+"/lists", params, "get lists", &lists); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+// CreateList creates a list on a board at a position ("top", "bottom" or a number)
+func (c *Client) CreateList(ctx context.Context, boardID, listName, position string) (*List, error) {
+	params := url.Values{}
+	params.Add("name", listName)
+	params.Add("pos", position)
+
+	var list *List
+	if err := c.sendRequest(ctx, http.MethodPost, "/boards/"+url.PathEscape(boardID)+"/lists", params, "create list", &list); err != nil {
+		return nil, err
+	}
+
+	if err := c.record(ObjectList, list.ID, list.Name); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// UpdateList renames a list
+func (c *Client) UpdateList(ctx context.Context, listID, listName string) (*List, error) {
+	params := url.Values{}
+	params.Add("name", listName)
+
+	var list *List
+	if err := c.sendRequest(ctx, http.MethodPut, "/lists/"+url.PathEscape(listID), params, "update list", &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ArchiveList closes a list. Trello has no way to delete a list.
+func (c *Client) ArchiveList(ctx context.Context, listID string) error {
+	params := url.Values{}
+	params.Add("closed", "true")
+
+	return c.sendRequest(ctx, http.MethodPut, "/lists/"+url.PathEscape(listID), params, "archive list", nil)
+}
+
+// GetCards returns the open cards of a list, in order
+func (c *Client) GetCards(ctx context.Context, listID string) ([]*Card, error) {
+	params := url.Values{}
+	params.Add("filter", "open")
+	params.Add("fields", cardFields)
+
+	var cards []*Card
+	if err := c.sendRequest(ctx, http.MethodGet, "/lists/"+url.PathEscape(listID)+"/cards", params, "get cards", &cards); err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+// GetCard returns a card by ID
+func (c *Client) GetCard(ctx context.Context, cardID string) (*Card, error) {
+	params := url.Values{}
+	params.Add("fields", cardFields)
+
+	var card *Card
+	if err := c.sendRequest(ctx, http.MethodGet, "/cards/"+url.PathEscape(cardID), params, "get card", &card); err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+// CreateCard creates a card with the name, description, labels and dates of card at the bottom of its list
+func (c *Client) CreateCard(ctx context.Context, card *Card) (*Card, error) {
+	params := getCardParams(card)
+	params.Add("pos", "bottom")
+
+	var created *Card
+	if err := c.sendRequest(ctx, http.MethodPost, "/cards", params, "create card", &created); err != nil {
+		return nil, err
+	}
+
+	if err := c.record(ObjectCard, created.ID, created.Name); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateCard sets the name, description, list, labels and dates of a card to the ones of card.
+// Dates that are nil are left unchanged.
+func (c *Client) UpdateCard(ctx context.Context, card *Card) (*Card, error) {
+	var updated *Card
+	if err := c.sendRequest(ctx, http.MethodPut, "/cards/"+url.PathEscape(card.ID), getCardParams(card), "update card", &updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// ArchiveCard closes a card, which can still be restored on Trello
+func (c *Client) ArchiveCard(ctx context.Context, cardID string) error {
+	params := url.Values{}
+	params.Add("closed", "true")
+
+	return c.sendRequest(ctx, http.MethodPut, "/cards/"+url.PathEscape(cardID), params, "archive card", nil)
+}
+
+// DeleteCard deletes a card permanently
+func (c *Client) DeleteCard(ctx context.Context, cardID string) error {
+	return c.sendRequest(ctx, http.MethodDelete, "/cards/"+url.PathEscape(cardID), nil, "delete card", nil)
+}
+
+// getCardParams returns the query parameters of the fields of a card
+func getCardParams(card *Card) url.Values {
+	params := url.Values{}
+	params.Add("name", card.Name)
+	params.Add("desc", card.Desc)
+	params.Add("idList", card.IDList)
+	params.Add("idLabels", strings.Join(card.IDLabels, ","))
+	if card.Start != nil {
+		params.Add("start", card.Start.UTC().Format(time.RFC3339))
+	}
+	if card.Due != nil {
+		params.Add("due", card.Due.UTC().Format(time.RFC3339))
+	}
+
+	return params
+}
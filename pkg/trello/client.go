@@ -0,0 +1,129 @@
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/transport"
+)
+
+var (
+	DefaultBaseURL   = "https://api.trello.com/1"
+	DefaultUserAgent = "holiday-planner-go"
+
+	// DefaultHTTPClient is the HTTP client of the clients without one, shared so that they stay under Trello's
+	// limit of 100 requests per 10 seconds per token, and retrying requests that failed with a 429 or a 5xx
+	DefaultHTTPClient = newDefaultHTTPClient()
+
+	rateLimit         = 100
+	rateLimitInterval = 10 * time.Second
+)
+
+// newDefaultHTTPClient returns an HTTP client with the rate limit of Trello and the default retries
+func newDefaultHTTPClient() *http.Client {
+	config := transport.DefaultConfig()
+	config.Rate, config.Interval = rateLimit, rateLimitInterval
+	return transport.NewClient(config)
+}
+
+// Config is the configuration of a Trello client. Empty fields are set to their defaults by NewClient.
+type Config struct {
+	Key        string
+	Token      string
+	BaseURL    string
+	HTTPClient *http.Client
+	UserAgent  string
+}
+
+// ConfigFromEnv returns a configuration with the API key and token of TRELLO_API_KEY and TRELLO_API_TOKEN
+func ConfigFromEnv() Config {
+	return Config{
+		Key:   os.Getenv("TRELLO_API_KEY"),
+		Token: os.Getenv("TRELLO_API_TOKEN"),
+	}
+}
+
+// Client is a client of the Trello REST API
+type Client struct {
+	key        string
+	token      string
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	// run records the objects created by the client, if not nil (see WithRun)
+	run *Run
+}
+
+// NewClient returns a client of the Trello API with a configuration
+func NewClient(config Config) *Client {
+	c := &Client{
+		key:        config.Key,
+		token:      config.Token,
+		baseURL:    strings.TrimSuffix(config.BaseURL, "/"),
+		httpClient: config.HTTPClient,
+		userAgent:  config.UserAgent,
+	}
+
+	if c.baseURL == "" {
+		c.baseURL = DefaultBaseURL
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = DefaultHTTPClient
+	}
+
+	if c.userAgent == "" {
+		c.userAgent = DefaultUserAgent
+	}
+
+	return c
+}
+
+// sendRequest sends an authenticated request to a path of the Trello API with params in the query string,
+// and decodes the response into v unless it is nil. The action (e.g. "create board") is part of the error if the request fails
+// (see APIError).
+func (c *Client) sendRequest(ctx context.Context, method, path string, params url.Values, action string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	q := req.URL.Query()
+	q.Add("key", c.key)
+	q.Add("token", c.token)
+	for k, values := range params {
+		for _, i := range values {
+			q.Add(k, i)
+		}
+	}
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return newAPIError(action, path, res.StatusCode, b)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,294 @@
+package trello
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		client := NewClient(Config{Key: "testKey", Token: "testToken"})
+		assert.Equal(t, DefaultBaseURL, client.baseURL)
+		assert.Equal(t, DefaultHTTPClient, client.httpClient)
+		assert.IsType(t, &transport.Transport{}, client.httpClient.Transport)
+		assert.Equal(t, DefaultUserAgent, client.userAgent)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		httpClient := &http.Client{}
+		client := NewClient(Config{BaseURL: "http://localhost/1/", HTTPClient: httpClient, UserAgent: "test"})
+		assert.Equal(t, "http://localhost/1", client.baseURL)
+		assert.Equal(t, httpClient, client.httpClient)
+		assert.Equal(t, "test", client.userAgent)
+	})
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("TRELLO_API_KEY", "envKey")
+		t.Setenv("TRELLO_API_TOKEN", "envToken")
+
+		client := NewClient(ConfigFromEnv())
+		assert.Equal(t, "envKey", client.key)
+		assert.Equal(t, "envToken", client.token)
+	})
+}
+
+func TestSendRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "testKey", r.URL.Query().Get("key"))
+		assert.Equal(t, "testToken", r.URL.Query().Get("token"))
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": "abc123a36eaf8d75e160000f", "name": "Holidays"}`))
+		assert.Nil(t, err)
+	}))
+	defer ts.Close()
+
+	client := NewClient(Config{Key: "testKey", Token: "testToken", BaseURL: ts.URL, UserAgent: "test-agent"})
+
+	t.Run("successful", func(t *testing.T) {
+		board, err := client.GetBoard(context.Background(), "abc123a36eaf8d75e160000f")
+		assert.Nil(t, err)
+		assert.Equal(t, &Board{ID: "abc123a36eaf8d75e160000f", Name: "Holidays"}, board)
+	})
+
+	t.Run("retried after too many requests", func(t *testing.T) {
+		attempts := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"id": "abc123a36eaf8d75e160000f", "name": "Holidays"}`))
+			assert.Nil(t, err)
+		}))
+		defer ts.Close()
+
+		board, err := NewClient(Config{BaseURL: ts.URL, HTTPClient: newTestHTTPClient()}).CreateBoard(context.Background(), "Holidays")
+		assert.Nil(t, err)
+		assert.Equal(t, "Holidays", board.Name)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		board, err := client.GetBoard(ctx, "abc123a36eaf8d75e160000f")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, board)
+	})
+}
+
+func TestClientMethods(t *testing.T) {
+	var method, path string
+	var query map[string][]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, query = r.Method, r.URL.Path, r.URL.Query()
+		response := `{}`
+		if r.Method == http.MethodGet {
+			response = `[]`
+		}
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(response))
+		assert.Nil(t, err)
+	}))
+	defer ts.Close()
+
+	client := NewClient(Config{BaseURL: ts.URL})
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		call           func() error
+		expectedMethod string
+		expectedPath   string
+		expectedQuery  map[string]string
+	}{
+		{
+			name: "update board",
+			call: func() error {
+				_, err := client.UpdateBoard(ctx, "board1", "Holidays 2025")
+				return err
+			},
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/boards/board1",
+			expectedQuery:  map[string]string{"name": "Holidays 2025"},
+		},
+		{
+			name:           "archive board",
+			call:           func() error { return client.ArchiveBoard(ctx, "board1") },
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/boards/board1",
+			expectedQuery:  map[string]string{"closed": "true"},
+		},
+		{
+			name:           "delete board",
+			call:           func() error { return client.DeleteBoard(ctx, "board1") },
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/boards/board1",
+		},
+		{
+			name: "update list",
+			call: func() error {
+				_, err := client.UpdateList(ctx, "list1", "Suggestions")
+				return err
+			},
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/lists/list1",
+			expectedQuery:  map[string]string{"name": "Suggestions"},
+		},
+		{
+			name:           "archive list",
+			call:           func() error { return client.ArchiveList(ctx, "list1") },
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/lists/list1",
+			expectedQuery:  map[string]string{"closed": "true"},
+		},
+		{
+			name: "update card",
+			call: func() error {
+				_, err := client.UpdateCard(ctx, &Card{ID: "card1", Name: "card", Desc: "description", IDList: "list1", IDLabels: []string{"label1", "label2"}})
+				return err
+			},
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/cards/card1",
+			expectedQuery:  map[string]string{"name": "card", "desc": "description", "idList": "list1", "idLabels": "label1,label2"},
+		},
+		{
+			name:           "delete card",
+			call:           func() error { return client.DeleteCard(ctx, "card1") },
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/cards/card1",
+		},
+		{
+			name: "get labels",
+			call: func() error {
+				_, err := client.GetLabels(ctx, "board1")
+				return err
+			},
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/boards/board1/labels",
+		},
+		{
+			name: "create label",
+			call: func() error {
+				_, err := client.CreateLabel(ctx, &Label{IDBoard: "board1", Name: "Summer", Color: "yellow"})
+				return err
+			},
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/labels",
+			expectedQuery:  map[string]string{"idBoard": "board1", "name": "Summer", "color": "yellow"},
+		},
+		{
+			name: "update label",
+			call: func() error {
+				_, err := client.UpdateLabel(ctx, &Label{ID: "label1", Name: "Winter", Color: "blue"})
+				return err
+			},
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/labels/label1",
+			expectedQuery:  map[string]string{"name": "Winter", "color": "blue"},
+		},
+		{
+			name:           "delete label",
+			call:           func() error { return client.DeleteLabel(ctx, "label1") },
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/labels/label1",
+		},
+		{
+			name: "get checklists",
+			call: func() error {
+				_, err := client.GetChecklists(ctx, "card1")
+				return err
+			},
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/cards/card1/checklists",
+		},
+		{
+			name: "create checklist",
+			call: func() error {
+				_, err := client.CreateChecklist(ctx, "card1", "Leaves")
+				return err
+			},
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/checklists",
+			expectedQuery:  map[string]string{"idCard": "card1", "name": "Leaves"},
+		},
+		{
+			name: "update checklist",
+			call: func() error {
+				_, err := client.UpdateChecklist(ctx, "checklist1", "Leave dates")
+				return err
+			},
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/checklists/checklist1",
+			expectedQuery:  map[string]string{"name": "Leave dates"},
+		},
+		{
+			name:           "delete checklist",
+			call:           func() error { return client.DeleteChecklist(ctx, "checklist1") },
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/checklists/checklist1",
+		},
+		{
+			name: "create check item",
+			call: func() error {
+				_, err := client.CreateCheckItem(ctx, "checklist1", "2024-10-04")
+				return err
+			},
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/checklists/checklist1/checkItems",
+			expectedQuery:  map[string]string{"name": "2024-10-04"},
+		},
+		{
+			name: "update check item",
+			call: func() error {
+				_, err := client.UpdateCheckItem(ctx, "card1", &CheckItem{ID: "item1", Name: "2024-10-04", State: CheckItemComplete})
+				return err
+			},
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/cards/card1/checkItem/item1",
+			expectedQuery:  map[string]string{"name": "2024-10-04", "state": "complete"},
+		},
+		{
+			name:           "delete check item",
+			call:           func() error { return client.DeleteCheckItem(ctx, "checklist1", "item1") },
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/checklists/checklist1/checkItems/item1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedMethod, method)
+			assert.Equal(t, tc.expectedPath, path)
+			for k, v := range tc.expectedQuery {
+				assert.Equal(t, v, query[k][0], k)
+			}
+		})
+	}
+}
+
+func TestGetDefaultClient(t *testing.T) {
+	origClient := DefaultClient
+	DefaultClient = nil
+	defer func() {
+		DefaultClient = origClient
+	}()
+
+	t.Setenv("TRELLO_API_KEY", "envKey")
+	client := GetDefaultClient()
+	assert.Equal(t, os.Getenv("TRELLO_API_KEY"), client.key)
+	assert.Equal(t, client, GetDefaultClient())
+}
@@ -0,0 +1,114 @@
+package trello
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Reason is the cause of a failed request to the Trello API
+type Reason string
+
+var (
+	ReasonInvalidKey    Reason = "invalid key"
+	ReasonInvalidToken  Reason = "invalid token"
+	ReasonUnauthorized  Reason = "unauthorized"
+	ReasonBoardNotFound Reason = "board not found"
+	ReasonNotFound      Reason = "not found"
+	ReasonRateLimited   Reason = "rate limited"
+	ReasonServerError   Reason = "server error"
+	ReasonUnknown       Reason = "unknown"
+
+	// maxErrorMessageLength is the most characters of a response body kept as the message of an error
+	maxErrorMessageLength = 200
+)
+
+// APIError is the error of a request to the Trello API that failed with a status code
+type APIError struct {
+	// Action is what the request did (e.g. "create card")
+	Action     string
+	StatusCode int
+	Reason     Reason
+	// Message is the error text of the response, if it had one
+	Message string
+}
+
+func (e *APIError) Error() string {
+	s := fmt.Sprintf("failed to %s - status code: %d", e.Action, e.StatusCode)
+	if e.Message != "" {
+		s += " - " + e.Message
+	}
+
+	return s
+}
+
+// Retryable checks if the request may succeed if it is sent again later
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// newAPIError returns the error of a request to a path that failed with a status code and a response body.
+// Trello answers with plain text (e.g. "invalid token") or, for rate limits, a JSON object with a message.
+func newAPIError(action, path string, statusCode int, body []byte) *APIError {
+	e := &APIError{Action: action, StatusCode: statusCode, Message: getErrorMessage(body)}
+	e.Reason = getReason(path, statusCode, e.Message)
+	return e
+}
+
+// getErrorMessage returns the error text of a response body
+func getErrorMessage(body []byte) string {
+	var v struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &v); err == nil && (v.Message != "" || v.Error != "") {
+		if v.Message != "" {
+			return v.Message
+		}
+		return v.Error
+	}
+
+	message := []rune(strings.TrimSpace(string(body)))
+	if len(message) > maxErrorMessageLength {
+		return string(message[:maxErrorMessageLength]) + "..."
+	}
+
+	return string(message)
+}
+
+// getReason returns the cause of a request to a path that failed with a status code and an error text
+func getReason(path string, statusCode int, message string) Reason {
+	message = strings.ToLower(message)
+
+	switch {
+	case statusCode == http.StatusUnauthorized && strings.Contains(message, "key"):
+		return ReasonInvalidKey
+	case statusCode == http.StatusUnauthorized && strings.Contains(message, "token"):
+		return ReasonInvalidToken
+	case statusCode == http.StatusUnauthorized:
+		return ReasonUnauthorized
+	case statusCode == http.StatusTooManyRequests:
+		return ReasonRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		return ReasonServerError
+	case isBoardPath(path) && (statusCode == http.StatusNotFound || message == "invalid id"):
+		return ReasonBoardNotFound
+	case statusCode == http.StatusNotFound:
+		return ReasonNotFound
+	default:
+		return ReasonUnknown
+	}
+}
+
+// isBoardPath checks if a path is of a board or of its objects (e.g. "/boards/abc/lists")
+func isBoardPath(path string) bool {
+	return strings.HasPrefix(path, "/boards/")
+}
+
+// isNotFound checks if a request failed because its object does not exist
+func isNotFound(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
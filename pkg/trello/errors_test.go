@@ -0,0 +1,75 @@
+package trello
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name              string
+		path              string
+		status            int
+		body              string
+		expectedReason    Reason
+		expectedMessage   string
+		expectedRetryable bool
+	}{
+		{name: "invalid key", path: "/boards", status: http.StatusUnauthorized, body: "invalid key", expectedReason: ReasonInvalidKey, expectedMessage: "invalid key"},
+		{name: "invalid token", path: "/boards", status: http.StatusUnauthorized, body: "invalid token\n", expectedReason: ReasonInvalidToken, expectedMessage: "invalid token"},
+		{name: "unauthorized", path: "/cards", status: http.StatusUnauthorized, body: "unauthorized permission requested", expectedReason: ReasonUnauthorized, expectedMessage: "unauthorized permission requested"},
+		{name: "board not found", path: "/boards/abc", status: http.StatusNotFound, body: "The requested resource was not found.", expectedReason: ReasonBoardNotFound, expectedMessage: "The requested resource was not found."},
+		{name: "invalid board ID", path: "/boards/abc/lists", status: http.StatusBadRequest, body: "invalid id", expectedReason: ReasonBoardNotFound, expectedMessage: "invalid id"},
+		{name: "card not found", path: "/cards/abc", status: http.StatusNotFound, expectedReason: ReasonNotFound},
+		{
+			name:              "rate limited",
+			path:              "/cards",
+			status:            http.StatusTooManyRequests,
+			body:              `{"error": "API_TOKEN_LIMIT_EXCEEDED", "message": "Rate limit exceeded"}`,
+			expectedReason:    ReasonRateLimited,
+			expectedMessage:   "Rate limit exceeded",
+			expectedRetryable: true,
+		},
+		{name: "server error", path: "/cards", status: http.StatusBadGateway, body: `{"error": "Bad Gateway"}`, expectedReason: ReasonServerError, expectedMessage: "Bad Gateway", expectedRetryable: true},
+		{name: "bad request", path: "/cards", status: http.StatusBadRequest, body: "invalid value for idList", expectedReason: ReasonUnknown, expectedMessage: "invalid value for idList"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newAPIError("test", tc.path, tc.status, []byte(tc.body))
+			assert.Equal(t, tc.expectedReason, e.Reason)
+			assert.Equal(t, tc.expectedMessage, e.Message)
+			assert.Equal(t, tc.expectedRetryable, e.Retryable())
+		})
+	}
+
+	t.Run("long message", func(t *testing.T) {
+		e := newAPIError("test", "/cards", http.StatusBadRequest, []byte(strings.Repeat("ä", 300)))
+		assert.Equal(t, strings.Repeat("ä", 200)+"...", e.Message)
+	})
+
+	t.Run("returned by requests", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, err := w.Write([]byte("invalid token"))
+			assert.Nil(t, err)
+		}))
+		defer ts.Close()
+
+		client := NewClient(Config{BaseURL: ts.URL, HTTPClient: http.DefaultClient})
+		_, err := client.CreateCard(context.Background(), &Card{Name: "card", IDList: "list1"})
+		assert.Equal(t, "failed to create card - status code: 401 - invalid token", err.Error())
+
+		var e *APIError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "create card", e.Action)
+		assert.Equal(t, ReasonInvalidToken, e.Reason)
+		assert.False(t, isNotFound(err))
+	})
+}
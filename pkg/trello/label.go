@@ -0,0 +1,253 @@
+package trello
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	CheckItemComplete   = "complete"
+	CheckItemIncomplete = "incomplete"
+)
+
+// Label is a label of a Trello board, which can be added to its cards
+type Label struct {
+	ID      string `json:"id"`
+	IDBoard string `json:"idBoard"`
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	// Prefix is the start of the names of the board's labels that the label replaces (e.g. "excellent:"), for labels
+	// whose name depends on settings. A board's label with the prefix is renamed instead of creating another one.
+	Prefix string `json:"-"`
+}
+
+// Checklist is a checklist of a Trello card
+type Checklist struct {
+	ID         string       `json:"id"`
+	IDCard     string       `json:"idCard"`
+	Name       string       `json:"name"`
+	CheckItems []*CheckItem `json:"checkItems"`
+}
+
+// CheckItem is an item of a checklist, with the state "complete" or "incomplete"
+type CheckItem struct {
+	ID          string `json:"id"`
+	IDChecklist string `json:"idChecklist"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+}
+
+// GetLabels returns the labels of a board
+func (c *Client) GetLabels(ctx context.Context, boardID string) ([]*Label, error) {
+	var labels []*Label
+	if err := c.sendRequest(ctx, http.MethodGet, "/boards/"+url.PathEscape(boardID)+"/labels", nil, "get labels", &labels); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// CreateLabel creates a label with the name and color of label on its board
+func (c *Client) CreateLabel(ctx context.Context, label *Label) (*Label, error) {
+	params := url.Values{}
+	params.Add("idBoard", label.IDBoard)
+	params.Add("name", label.Name)
+	params.Add("color", label.Color)
+
+	var created *Label
+	if err := c.sendRequest(ctx, http.MethodPost, "/labels", params, "create label", &created); err != nil {
+		return nil, err
+	}
+
+	if err := c.record(ObjectLabel, created.ID, created.Name); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateLabel sets the name and color of a label to the ones of label
+func (c *Client) UpdateLabel(ctx context.Context, label *Label) (*Label, error) {
+	params := url.Values{}
+	params.Add("name", label.Name)
+	params.Add("color", label.Color)
+
+	var updated *Label
+	if err := c.sendRequest(ctx, http.MethodPut, "/labels/"+url.PathEscape(label.ID), params, "update label", &updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteLabel deletes a label and removes it from the cards. Trello has no way to archive a label.
+func (c *Client) DeleteLabel(ctx context.Context, labelID string) error {
+	return c.sendRequest(ctx, http.MethodDelete, "/labels/"+url.PathEscape(labelID), nil, "delete label", nil)
+}
+
+// EnsureLabels creates the labels that a board has no label with the same name of, updates the color of the ones
+// that have another color, and sets the ID of each label to the one of the board's label. A label with a Prefix
+// renames a board's label with the prefix if there is none with the same name.
+func (c *Client) EnsureLabels(ctx context.Context, boardID string, labels []*Label) error {
+	_, err := c.ensureLabels(ctx, boardID, labels)
+	return err
+}
+
+// ensureLabels is EnsureLabels that also returns the IDs of the board's labels with the prefix of a label that
+// were not reused (e.g. named after former settings), which no card should have anymore
+func (c *Client) ensureLabels(ctx context.Context, boardID string, labels []*Label) ([]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	existing, err := c.GetLabels(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, l := range labels {
+		names[l.Name] = true
+	}
+
+	byName := map[string]*Label{}
+	for _, l := range existing {
+		if _, ok := byName[l.Name]; l.Name != "" && !ok {
+			byName[l.Name] = l
+		}
+	}
+
+	used := map[string]bool{}
+	for _, l := range labels {
+		e, ok := byName[l.Name]
+		if !ok && l.Prefix != "" {
+			e, ok = findRenamedLabel(existing, l.Prefix, names, used)
+		}
+
+		switch {
+		case !ok:
+			if e, err = c.CreateLabel(ctx, &Label{IDBoard: boardID, Name: l.Name, Color: l.Color}); err != nil {
+				return nil, err
+			}
+		case e.Name != l.Name || e.Color != l.Color:
+			if _, err = c.UpdateLabel(ctx, &Label{ID: e.ID, Name: l.Name, Color: l.Color}); err != nil {
+				return nil, err
+			}
+		}
+
+		used[e.ID] = true
+		l.ID, l.IDBoard = e.ID, boardID
+		byName[l.Name] = &Label{ID: e.ID, IDBoard: boardID, Name: l.Name, Color: l.Color}
+	}
+
+	var stale []string
+	for _, e := range existing {
+		if !used[e.ID] && hasLabelPrefix(labels, e.Name) {
+			stale = append(stale, e.ID)
+		}
+	}
+
+	return stale, nil
+}
+
+// findRenamedLabel returns the first label with a prefix that is not used and whose name is none of names,
+// and false if there is none
+func findRenamedLabel(labels []*Label, prefix string, names, used map[string]bool) (*Label, bool) {
+	for _, l := range labels {
+		if strings.HasPrefix(l.Name, prefix) && !names[l.Name] && !used[l.ID] {
+			return l, true
+		}
+	}
+
+	return nil, false
+}
+
+// hasLabelPrefix checks if a name starts with the prefix of one of the labels
+func hasLabelPrefix(labels []*Label, name string) bool {
+	for _, l := range labels {
+		if l.Prefix != "" && strings.HasPrefix(name, l.Prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetChecklists returns the checklists of a card with their items
+func (c *Client) GetChecklists(ctx context.Context, cardID string) ([]*Checklist, error) {
+	var checklists []*Checklist
+	if err := c.sendRequest(ctx, http.MethodGet, "/cards/"+url.PathEscape(cardID)+"/checklists", nil, "get checklists", &checklists); err != nil {
+		return nil, err
+	}
+
+	return checklists, nil
+}
+
+// CreateChecklist creates an empty checklist on a card
+func (c *Client) CreateChecklist(ctx context.Context, cardID, name string) (*Checklist, error) {
+	params := url.Values{}
+	params.Add("idCard", cardID)
+	params.Add("name", name)
+
+	var checklist *Checklist
+	if err := c.sendRequest(ctx, http.MethodPost, "/checklists", params, "create checklist", &checklist); err != nil {
+		return nil, err
+	}
+
+	return checklist, nil
+}
+
+// UpdateChecklist renames a checklist
+func (c *Client) UpdateChecklist(ctx context.Context, checklistID, name string) (*Checklist, error) {
+	params := url.Values{}
+	params.Add("name", name)
+
+	var checklist *Checklist
+	if err := c.sendRequest(ctx, http.MethodPut, "/checklists/"+url.PathEscape(checklistID), params, "update checklist", &checklist); err != nil {
+		return nil, err
+	}
+
+	return checklist, nil
+}
+
+// DeleteChecklist deletes a checklist with its items. Trello has no way to archive a checklist.
+func (c *Client) DeleteChecklist(ctx context.Context, checklistID string) error {
+	return c.sendRequest(ctx, http.MethodDelete, "/checklists/"+url.PathEscape(checklistID), nil, "delete checklist", nil)
+}
+
+// CreateCheckItem adds an incomplete item at the bottom of a checklist
+func (c *Client) CreateCheckItem(ctx context.Context, checklistID, name string) (*CheckItem, error) {
+	params := url.Values{}
+	params.Add("name", name)
+	params.Add("pos", "bottom")
+
+	var item *CheckItem
+	if err := c.sendRequest(ctx, http.MethodPost, "/checklists/"+url.PathEscape(checklistID)+"/checkItems", params, "create check item", &item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// UpdateCheckItem sets the name and state of an item of a card's checklist to the ones of item
+func (c *Client) UpdateCheckItem(ctx context.Context, cardID string, item *CheckItem) (*CheckItem, error) {
+	params := url.Values{}
+	params.Add("name", item.Name)
+	params.Add("state", item.State)
+
+	var updated *CheckItem
+	path := "/cards/" + url.PathEscape(cardID) + "/checkItem/" + url.PathEscape(item.ID)
+	if err := c.sendRequest(ctx, http.MethodPut, path, params, "update check item", &updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteCheckItem deletes an item of a checklist
+func (c *Client) DeleteCheckItem(ctx context.Context, checklistID, checkItemID string) error {
+	path := "/checklists/" + url.PathEscape(checklistID) + "/checkItems/" + url.PathEscape(checkItemID)
+	return c.sendRequest(ctx, http.MethodDelete, path, nil, "delete check item", nil)
+}
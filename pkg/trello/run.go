@@ -0,0 +1,277 @@
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// RunsDir is the directory of the journals of runs, the user cache directory (e.g. $XDG_CACHE_HOME) if empty
+	RunsDir = ""
+
+	ObjectBoard = "board"
+	ObjectList  = "list"
+	ObjectCard  = "card"
+	ObjectLabel = "label"
+
+	runsDirName   = filepath.Join("holiday-planner-go", "trello-runs")
+	runIDFormat   = "20060102-150405.000"
+	runFileExt    = ".json"
+	runFileMode   = os.FileMode(0o644)
+	errRunMissing = errors.New("run not found")
+)
+
+// Object is a board, list, card or label created by a run
+type Object struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Run is the journal of the objects created on Trello by a run, which is saved after every object so that a run
+// that stopped halfway can be resumed or undone
+type Run struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	// BoardID is the board the run publishes to
+	BoardID   string    `json:"boardId"`
+	Completed bool      `json:"completed"`
+	Created   []*Object `json:"created"`
+}
+
+// GetRunsDir returns the directory of the journals of runs
+func GetRunsDir() (string, error) {
+	if RunsDir != "" {
+		return RunsDir, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, runsDirName), nil
+}
+
+// NewRun returns a run that starts now, identified by its start time
+func NewRun() *Run {
+	now := time.Now().UTC()
+	return &Run{ID: now.Format(runIDFormat), StartedAt: now}
+}
+
+// LoadRun returns the run with an ID, or the latest run if the ID is "last"
+func LoadRun(runID string) (*Run, error) {
+	if runID == "last" {
+		runs, err := ListRuns()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(runs) == 0 {
+			return nil, errRunMissing
+		}
+
+		return runs[len(runs)-1], nil
+	}
+
+	dir, err := GetRunsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := readRun(filepath.Join(dir, runID+runFileExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", errRunMissing, runID)
+	}
+
+	return run, err
+}
+
+// ListRuns returns the saved runs, oldest first
+func ListRuns() ([]*Run, error) {
+	dir, err := GetRunsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*Run
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), runFileExt) {
+			continue
+		}
+
+		run, err := readRun(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartedAt.Before(runs[j].StartedAt)
+	})
+
+	return runs, nil
+}
+
+// GetIncompleteRun returns the latest run if it stopped before it completed, otherwise nil
+func GetIncompleteRun() (*Run, error) {
+	runs, err := ListRuns()
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+
+	if last := runs[len(runs)-1]; !last.Completed {
+		return last, nil
+	}
+
+	return nil, nil
+}
+
+// readRun reads a run from a file
+func readRun(filePath string) (*Run, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var run *Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+
+	if run == nil {
+		return nil, fmt.Errorf("invalid run: %s", filePath)
+	}
+
+	return run, nil
+}
+
+// Save writes the run into its file, creating the runs directory if needed
+func (r *Run) Save() error {
+	dir, err := GetRunsDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	s, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, r.ID+runFileExt), s, runFileMode)
+}
+
+// Complete marks the run as completed, so that it is not resumed
+func (r *Run) Complete() error {
+	r.Completed = true
+	return r.Save()
+}
+
+// Remove deletes the file of the run
+func (r *Run) Remove() error {
+	dir, err := GetRunsDir()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(dir, r.ID+runFileExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// hasCreated checks if the run created an object
+func (r *Run) hasCreated(kind, id string) bool {
+	for _, o := range r.Created {
+		if o.Kind == kind && o.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithRun returns a copy of the client that records the objects it creates in a run
+func (c *Client) WithRun(run *Run) *Client {
+	client := *c
+	client.run = run
+	return &client
+}
+
+// record adds an object created by the client to its run and saves it
+func (c *Client) record(kind, id, name string) error {
+	if c.run == nil {
+		return nil
+	}
+
+	if kind == ObjectBoard {
+		c.run.BoardID = id
+	}
+	c.run.Created = append(c.run.Created, &Object{Kind: kind, ID: id, Name: name})
+
+	return c.run.Save()
+}
+
+// useBoard sets the board of the client's run
+func (c *Client) useBoard(boardID string) error {
+	if c.run == nil || c.run.BoardID == boardID {
+		return nil
+	}
+
+	c.run.BoardID = boardID
+	return c.run.Save()
+}
+
+// Undo removes the objects created by a run, newest first, and deletes the run. A board created by the run is deleted
+// with everything on it, cards and labels are deleted, and lists are archived since Trello has no way to delete a list.
+// Objects that no longer exist are skipped.
+func (c *Client) Undo(ctx context.Context, run *Run) error {
+	boardCreated := run.hasCreated(ObjectBoard, run.BoardID)
+
+	for i := len(run.Created) - 1; i >= 0; i-- {
+		o := run.Created[i]
+		if boardCreated && o.Kind != ObjectBoard {
+			continue
+		}
+
+		var err error
+		switch o.Kind {
+		case ObjectBoard:
+			err = c.DeleteBoard(ctx, o.ID)
+		case ObjectList:
+			err = c.ArchiveList(ctx, o.ID)
+		case ObjectCard:
+			err = c.DeleteCard(ctx, o.ID)
+		case ObjectLabel:
+			err = c.DeleteLabel(ctx, o.ID)
+		}
+
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	return run.Remove()
+}
@@ -0,0 +1,178 @@
+package trello
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setRunsDir saves runs into a temporary directory until the test ends
+func setRunsDir(t *testing.T) string {
+	origDir := RunsDir
+	RunsDir = t.TempDir()
+	t.Cleanup(func() {
+		RunsDir = origDir
+	})
+
+	return RunsDir
+}
+
+func TestRuns(t *testing.T) {
+	dir := setRunsDir(t)
+
+	t.Run("no runs", func(t *testing.T) {
+		runs, err := ListRuns()
+		assert.Nil(t, err)
+		assert.Empty(t, runs)
+
+		run, err := GetIncompleteRun()
+		assert.Nil(t, err)
+		assert.Nil(t, run)
+
+		run, err = LoadRun("last")
+		assert.Equal(t, "run not found", err.Error())
+		assert.Nil(t, run)
+	})
+
+	first := &Run{ID: "first", StartedAt: time.Date(2024, time.October, 1, 8, 0, 0, 0, time.UTC), Completed: true}
+	second := &Run{ID: "second", StartedAt: time.Date(2024, time.October, 2, 8, 0, 0, 0, time.UTC), BoardID: "board1"}
+	assert.Nil(t, second.Save())
+	assert.Nil(t, first.Save())
+
+	t.Run("list", func(t *testing.T) {
+		runs, err := ListRuns()
+		assert.Nil(t, err)
+		assert.Equal(t, []*Run{first, second}, runs)
+	})
+
+	t.Run("load", func(t *testing.T) {
+		run, err := LoadRun("first")
+		assert.Nil(t, err)
+		assert.Equal(t, first, run)
+
+		run, err = LoadRun("last")
+		assert.Nil(t, err)
+		assert.Equal(t, second, run)
+
+		run, err = LoadRun("missing")
+		assert.Equal(t, "run not found: missing", err.Error())
+		assert.Nil(t, run)
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		run, err := GetIncompleteRun()
+		assert.Nil(t, err)
+		assert.Equal(t, second, run)
+
+		assert.Nil(t, second.Complete())
+		run, err = GetIncompleteRun()
+		assert.Nil(t, err)
+		assert.Nil(t, run)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("null"), 0o600)
+		assert.Nil(t, err)
+		defer os.Remove(filepath.Join(dir, "invalid.json"))
+
+		runs, err := ListRuns()
+		assert.Contains(t, err.Error(), "invalid run")
+		assert.Nil(t, runs)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		assert.Nil(t, first.Remove())
+		assert.Nil(t, first.Remove())
+
+		runs, err := ListRuns()
+		assert.Nil(t, err)
+		assert.Equal(t, []*Run{second}, runs)
+	})
+
+	t.Run("new run", func(t *testing.T) {
+		run := NewRun()
+		assert.Equal(t, run.StartedAt.Format(runIDFormat), run.ID)
+		assert.False(t, run.Completed)
+	})
+}
+
+func TestUndo(t *testing.T) {
+	lists := []*ListPlan{
+		{Name: ListSuggestions, Cards: []*CardPlan{
+			{Key: "2024-10-03 - 2024-10-06 ", Name: "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days", Labels: []*Label{{Name: "excellent", Color: "green"}}},
+		}},
+	}
+
+	t.Run("created board", func(t *testing.T) {
+		setRunsDir(t)
+		f, client := newFakeTrello(t)
+		f.boards = append(f.boards, &Board{ID: "other", Name: "Groceries"})
+
+		run := NewRun()
+		_, err := client.WithRun(run).SyncBoard(context.Background(), "", DefaultBoardName, lists)
+		assert.Nil(t, err)
+		assert.Equal(t, "id1", run.BoardID)
+		assert.Equal(t, []*Object{
+			{Kind: ObjectBoard, ID: "id1", Name: DefaultBoardName},
+			{Kind: ObjectLabel, ID: "id2", Name: "excellent"},
+			{Kind: ObjectList, ID: "id3", Name: ListSuggestions},
+			{Kind: ObjectCard, ID: "id4", Name: "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days"},
+		}, run.Created)
+
+		// the run is saved after every object
+		saved, err := LoadRun(run.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, len(saved.Created))
+
+		err = client.Undo(context.Background(), saved)
+		assert.Nil(t, err)
+		assert.Equal(t, []*Board{{ID: "other", Name: "Groceries"}}, f.boards)
+
+		runs, err := ListRuns()
+		assert.Nil(t, err)
+		assert.Empty(t, runs)
+	})
+
+	t.Run("existing board", func(t *testing.T) {
+		setRunsDir(t)
+		f, client := newFakeTrello(t)
+		f.boards = append(f.boards, &Board{ID: "board1", Name: DefaultBoardName})
+		f.lists["board1"] = []*List{{ID: "list1", Name: ListVacationWithoutLeaves}}
+		f.cards["list1"] = []*Card{{ID: "card1", Name: "2024-11-01 - 2024-11-03 -> 3 days"}}
+
+		run := NewRun()
+		_, err := client.WithRun(run).SyncBoard(context.Background(), "", DefaultBoardName,
+			append([]*ListPlan{{Name: ListVacationWithoutLeaves, Cards: []*CardPlan{{Name: "2024-11-01 - 2024-11-03 -> 3 days"}}}}, lists...))
+		assert.Nil(t, err)
+		assert.Equal(t, "board1", run.BoardID)
+
+		// a card deleted by hand is skipped
+		f.cards[f.lists["board1"][1].ID] = nil
+
+		err = client.Undo(context.Background(), run)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(f.boards))
+		assert.Empty(t, f.labels["board1"])
+		assert.Equal(t, []string{"2024-11-01 - 2024-11-03 -> 3 days"}, f.getCardNames("board1", ListVacationWithoutLeaves))
+		assert.True(t, f.lists["board1"][1].Closed)
+	})
+
+	t.Run("failed to delete", func(t *testing.T) {
+		setRunsDir(t)
+		f, client := newFakeTrello(t)
+		f.failPath = "/boards/board1"
+
+		run := &Run{ID: "failed", BoardID: "board1", Created: []*Object{{Kind: ObjectBoard, ID: "board1"}}}
+		assert.Nil(t, run.Save())
+
+		err := client.Undo(context.Background(), run)
+		assert.Equal(t, "failed to delete board - status code: 500", err.Error())
+
+		_, err = LoadRun("failed")
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,394 @@
+package trello
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChecklistLeaves is the name of the checklist of a card's Checklist items
+var ChecklistLeaves = "Leave requests"
+
+// CardPlan is a card that a list should have. Existing cards are matched by their name starting with Key
+// (e.g. the dates of a vacation), so that a card whose details changed is updated instead of replaced.
+type CardPlan struct {
+	Key   string
+	Name  string
+	Desc  string
+	Start *time.Time
+	Due   *time.Time
+	// Checklist are the items of the card's ChecklistLeaves checklist (e.g. the dates to request leaves for).
+	// Items that are already on the card keep their state.
+	Checklist []string
+	// Labels are the labels of the card by name and color, which are created on the board if it has none with the same name
+	Labels []*Label
+}
+
+// ListPlan is a list that a board should have, with its cards in order
+type ListPlan struct {
+	Name  string
+	Cards []*CardPlan
+	// Labels are the labels that the cards of the list may have, which are removed from the cards that do not have them
+	// in their plan. Other labels of the cards (e.g. added by hand) are kept.
+	Labels []*Label
+	// Manual lists (e.g. ListBooked) are created even without cards, and their cards are left as they are.
+	// Cards of the other lists that were moved to a manual list are not created again.
+	Manual bool
+}
+
+// SyncReport counts the changes made to a board by SyncBoard
+type SyncReport struct {
+	BoardID   string
+	Created   int
+	Updated   int
+	Archived  int
+	Unchanged int
+}
+
+// FindBoard returns the ID of the first open board with a name, or an empty string if there is none
+func (c *Client) FindBoard(ctx context.Context, boardName string) (string, error) {
+	boards, err := c.GetBoards(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, b := range boards {
+		if b.Name == boardName && !b.Closed {
+			return b.ID, nil
+		}
+	}
+
+	return "", nil
+}
+
+// SyncBoard makes a board converge to lists of cards instead of creating a new board every run.
+// The board is the one of boardID if given, otherwise the first open board named boardName, which is created if there is none.
+// Lists are reused by name, and missing lists are created after the existing ones unless they have no cards.
+// Cards are created, updated or archived so that each list has the cards of its plan, except for cards moved
+// to a manual list, and labels are created on the board if it has none with the same name.
+func (c *Client) SyncBoard(ctx context.Context, boardID, boardName string, lists []*ListPlan) (*SyncReport, error) {
+	boardID, err := c.getSyncBoard(ctx, boardID, boardName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.useBoard(boardID); err != nil {
+		return nil, err
+	}
+
+	labels := getPlanLabels(lists)
+	stale, err := c.ensureLabels(ctx, boardID, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	// labels that were replaced (e.g. after the settings they are named after changed) are removed from the cards
+	managedLabels := map[string]bool{}
+	for _, l := range labels {
+		managedLabels[l.ID] = true
+	}
+	for _, id := range stale {
+		managedLabels[id] = true
+	}
+
+	existingLists, err := c.GetLists(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	listIDs := make([]string, len(lists))
+	for i, l := range lists {
+		for _, e := range existingLists {
+			if e.Name == l.Name {
+				listIDs[i] = e.ID
+				break
+			}
+		}
+
+		if listIDs[i] != "" || (len(l.Cards) == 0 && !l.Manual) {
+			continue
+		}
+
+		list, err := c.CreateList(ctx, boardID, l.Name, "bottom")
+		if err != nil {
+			return nil, err
+		}
+		listIDs[i] = list.ID
+	}
+
+	moved, err := c.getMovedCards(ctx, lists, listIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{BoardID: boardID}
+	for i, l := range lists {
+		if l.Manual || listIDs[i] == "" {
+			continue
+		}
+
+		cards := withoutMovedCards(l.Cards, moved)
+		report.Unchanged += len(l.Cards) - len(cards)
+		if err := c.syncCards(ctx, listIDs[i], cards, managedLabels, report); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// getSyncBoard returns boardID if the board is open, otherwise the ID of the board named boardName, which is created if there is none
+func (c *Client) getSyncBoard(ctx context.Context, boardID, boardName string) (string, error) {
+	if boardID != "" {
+		board, err := c.GetBoard(ctx, boardID)
+		if err != nil {
+			return "", err
+		}
+
+		if board.Closed {
+			return "", fmt.Errorf("board is closed: %s", boardID)
+		}
+
+		return board.ID, nil
+	}
+
+	boardID, err := c.FindBoard(ctx, boardName)
+	if err != nil || boardID != "" {
+		return boardID, err
+	}
+
+	board, err := c.CreateBoard(ctx, boardName)
+	if err != nil {
+		return "", err
+	}
+
+	return board.ID, nil
+}
+
+// getPlanLabels returns the labels of lists and of their cards
+func getPlanLabels(lists []*ListPlan) []*Label {
+	var labels []*Label
+	for _, l := range lists {
+		labels = append(labels, l.Labels...)
+		for _, c := range l.Cards {
+			labels = append(labels, c.Labels...)
+		}
+	}
+
+	return labels
+}
+
+// syncCards creates, updates or archives the cards of a list so that it has the cards of a plan.
+// Labels of managedLabels that are not in the plan of a card are removed from it.
+func (c *Client) syncCards(ctx context.Context, listID string, cards []*CardPlan, managedLabels map[string]bool, report *SyncReport) error {
+	existing, err := c.GetCards(ctx, listID)
+	if err != nil {
+		return err
+	}
+
+	matches := make([]*Card, len(cards))
+	matched := map[string]bool{}
+
+	// cards with the same name are kept as they are
+	for i, p := range cards {
+		for _, e := range existing {
+			if !matched[e.ID] && e.Name == p.Name {
+				matches[i] = e
+				matched[e.ID] = true
+				break
+			}
+		}
+	}
+
+	// cards with the same key are updated
+	for i, p := range cards {
+		if matches[i] != nil || p.Key == "" {
+			continue
+		}
+
+		for _, e := range existing {
+			if !matched[e.ID] && strings.HasPrefix(e.Name, p.Key) {
+				matches[i] = e
+				matched[e.ID] = true
+				break
+			}
+		}
+	}
+
+	for i, p := range cards {
+		switch {
+		case matches[i] == nil:
+			if _, err := c.CreateCardPlan(ctx, listID, p); err != nil {
+				return err
+			}
+			report.Created++
+			continue
+		case !isCardUpToDate(matches[i], p, managedLabels):
+			card := *matches[i]
+			card.Name, card.Desc, card.Start, card.Due = p.Name, p.Desc, p.Start, p.Due
+			card.IDLabels = getCardLabels(matches[i], p, managedLabels)
+			if _, err := c.UpdateCard(ctx, &card); err != nil {
+				return err
+			}
+			report.Updated++
+		default:
+			report.Unchanged++
+		}
+
+		if len(p.Checklist) > 0 {
+			if err := c.syncChecklist(ctx, matches[i].ID, p.Checklist); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, e := range existing {
+		if matched[e.ID] {
+			continue
+		}
+
+		if err := c.ArchiveCard(ctx, e.ID); err != nil {
+			return err
+		}
+		report.Archived++
+	}
+
+	return nil
+}
+
+// CreateCardPlan creates the card of a plan with its checklist at the bottom of a list
+func (c *Client) CreateCardPlan(ctx context.Context, listID string, p *CardPlan) (*Card, error) {
+	card, err := c.CreateCard(ctx, &Card{IDList: listID, Name: p.Name, Desc: p.Desc, Start: p.Start, Due: p.Due, IDLabels: getLabelIDs(p.Labels)})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.Checklist) == 0 {
+		return card, nil
+	}
+
+	checklist, err := c.CreateChecklist(ctx, card.ID, ChecklistLeaves)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range p.Checklist {
+		if _, err := c.CreateCheckItem(ctx, checklist.ID, i); err != nil {
+			return nil, err
+		}
+	}
+
+	return card, nil
+}
+
+// isCardUpToDate checks if a card has the name, description, dates and labels of a plan
+func isCardUpToDate(card *Card, p *CardPlan, managedLabels map[string]bool) bool {
+	return card.Name == p.Name && card.Desc == p.Desc && isSameTime(card.Start, p.Start) && isSameTime(card.Due, p.Due) &&
+		isSameSet(card.IDLabels, getCardLabels(card, p, managedLabels))
+}
+
+// getCardLabels returns the IDs of the labels of a plan and of the labels of a card that are not managed by the plan
+func getCardLabels(card *Card, p *CardPlan, managedLabels map[string]bool) []string {
+	labels := getLabelIDs(p.Labels)
+	for _, i := range card.IDLabels {
+		if !managedLabels[i] {
+			labels = append(labels, i)
+		}
+	}
+
+	return labels
+}
+
+// getLabelIDs returns the distinct IDs of labels
+func getLabelIDs(labels []*Label) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, l := range labels {
+		if l.ID != "" && !seen[l.ID] {
+			ids = append(ids, l.ID)
+			seen[l.ID] = true
+		}
+	}
+
+	return ids
+}
+
+// isSameSet checks if two lists have the same distinct items
+func isSameSet(a, b []string) bool {
+	set := map[string]bool{}
+	for _, i := range a {
+		set[i] = true
+	}
+
+	other := map[string]bool{}
+	for _, i := range b {
+		if !set[i] {
+			return false
+		}
+		other[i] = true
+	}
+
+	return len(set) == len(other)
+}
+
+// isSameTime checks if two optional times are both nil or equal
+func isSameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// syncChecklist adds the missing items to the ChecklistLeaves checklist of a card and deletes the items that are not in the plan,
+// so that the state of the remaining items (e.g. a leave request that is ticked off as approved) is kept
+func (c *Client) syncChecklist(ctx context.Context, cardID string, items []string) error {
+	checklists, err := c.GetChecklists(ctx, cardID)
+	if err != nil {
+		return err
+	}
+
+	var checklist *Checklist
+	for _, i := range checklists {
+		if i.Name == ChecklistLeaves {
+			checklist = i
+			break
+		}
+	}
+
+	if checklist == nil {
+		if checklist, err = c.CreateChecklist(ctx, cardID, ChecklistLeaves); err != nil {
+			return err
+		}
+	}
+
+	existing := map[string]bool{}
+	planned := map[string]bool{}
+	for _, i := range items {
+		planned[i] = true
+	}
+
+	for _, i := range checklist.CheckItems {
+		if planned[i.Name] && !existing[i.Name] {
+			existing[i.Name] = true
+			continue
+		}
+
+		if err := c.DeleteCheckItem(ctx, checklist.ID, i.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, i := range items {
+		if existing[i] {
+			continue
+		}
+
+		if _, err := c.CreateCheckItem(ctx, checklist.ID, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
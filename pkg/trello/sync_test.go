@@ -0,0 +1,495 @@
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTrello is an in-memory Trello API with boards, lists, cards, checklists and labels
+type fakeTrello struct {
+	// failPath is a path that fails with an internal server error
+	failPath   string
+	boards     []*Board
+	lists      map[string][]*List
+	cards      map[string][]*Card
+	checklists map[string][]*Checklist
+	labels     map[string][]*Label
+	nextID     int
+}
+
+// newFakeTrello starts a fake Trello API and returns it with a client of it
+func newFakeTrello(t *testing.T) (*fakeTrello, *Client) {
+	f := &fakeTrello{lists: map[string][]*List{}, cards: map[string][]*Card{}, checklists: map[string][]*Checklist{}, labels: map[string][]*Label{}}
+	ts := httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(ts.Close)
+
+	return f, NewClient(Config{Key: "testKey", Token: "testToken", BaseURL: ts.URL, HTTPClient: newTestHTTPClient()})
+}
+
+// newTestHTTPClient returns an HTTP client that retries once without waiting
+func newTestHTTPClient() *http.Client {
+	return transport.NewClient(transport.Config{MaxRetries: 1, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+}
+
+func (f *fakeTrello) handle(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if r.URL.Path == f.failPath {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var response interface{}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/members/me/boards":
+		response = f.boards
+	case r.Method == http.MethodPost && r.URL.Path == "/boards/":
+		board := &Board{ID: f.newID(), Name: q.Get("name")}
+		f.boards = append(f.boards, board)
+		response = board
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "boards":
+		for _, b := range f.boards {
+			if b.ID == parts[1] {
+				response = b
+			}
+		}
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "lists":
+		lists := []*List{}
+		for _, l := range f.lists[parts[1]] {
+			if !l.Closed {
+				lists = append(lists, l)
+			}
+		}
+		response = lists
+	case r.Method == http.MethodPut && len(parts) == 2 && parts[0] == "lists":
+		for _, lists := range f.lists {
+			for _, l := range lists {
+				if l.ID == parts[1] {
+					l.Closed = q.Get("closed") == "true"
+					response = l
+				}
+			}
+		}
+	case r.Method == http.MethodDelete && len(parts) == 2 && parts[0] == "boards":
+		for i, b := range f.boards {
+			if b.ID == parts[1] {
+				f.boards = append(f.boards[:i], f.boards[i+1:]...)
+				delete(f.lists, b.ID)
+				response = struct{}{}
+				break
+			}
+		}
+	case r.Method == http.MethodDelete && len(parts) == 2 && parts[0] == "cards":
+		for listID, cards := range f.cards {
+			for i, c := range cards {
+				if c.ID == parts[1] {
+					f.cards[listID] = append(cards[:i], cards[i+1:]...)
+					response = struct{}{}
+					break
+				}
+			}
+		}
+	case r.Method == http.MethodDelete && len(parts) == 2 && parts[0] == "labels":
+		for boardID, labels := range f.labels {
+			for i, l := range labels {
+				if l.ID == parts[1] {
+					f.labels[boardID] = append(labels[:i], labels[i+1:]...)
+					response = struct{}{}
+					break
+				}
+			}
+		}
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "lists":
+		list := &List{ID: f.newID(), Name: q.Get("name")}
+		f.lists[parts[1]] = append(f.lists[parts[1]], list)
+		response = list
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "cards":
+		var cards []*Card
+		for _, c := range f.cards[parts[1]] {
+			if !c.Closed {
+				cards = append(cards, c)
+			}
+		}
+		response = cards
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "cards":
+		if card := f.getCard(parts[1]); card != nil {
+			response = card
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/cards":
+		card := &Card{ID: f.newID(), IDList: q.Get("idList")}
+		f.setCardFields(card, q)
+		f.cards[card.IDList] = append(f.cards[card.IDList], card)
+		response = card
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "labels":
+		response = f.labels[parts[1]]
+	case r.Method == http.MethodPost && r.URL.Path == "/labels":
+		label := &Label{ID: f.newID(), IDBoard: q.Get("idBoard"), Name: q.Get("name"), Color: q.Get("color")}
+		f.labels[label.IDBoard] = append(f.labels[label.IDBoard], label)
+		response = label
+	case r.Method == http.MethodPut && len(parts) == 2 && parts[0] == "labels":
+		for _, l := range f.labels {
+			for _, label := range l {
+				if label.ID == parts[1] {
+					label.Name, label.Color = q.Get("name"), q.Get("color")
+					response = label
+				}
+			}
+		}
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "checklists":
+		response = f.checklists[parts[1]]
+	case r.Method == http.MethodPost && r.URL.Path == "/checklists":
+		checklist := &Checklist{ID: f.newID(), IDCard: q.Get("idCard"), Name: q.Get("name")}
+		f.checklists[checklist.IDCard] = append(f.checklists[checklist.IDCard], checklist)
+		response = checklist
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "checkItems":
+		if checklist := f.getChecklist(parts[1]); checklist != nil {
+			item := &CheckItem{ID: f.newID(), IDChecklist: checklist.ID, Name: q.Get("name"), State: CheckItemIncomplete}
+			checklist.CheckItems = append(checklist.CheckItems, item)
+			response = item
+		}
+	case r.Method == http.MethodDelete && len(parts) == 4 && parts[2] == "checkItems":
+		if checklist := f.getChecklist(parts[1]); checklist != nil {
+			for i, item := range checklist.CheckItems {
+				if item.ID == parts[3] {
+					checklist.CheckItems = append(checklist.CheckItems[:i], checklist.CheckItems[i+1:]...)
+					response = struct{}{}
+					break
+				}
+			}
+		}
+	case r.Method == http.MethodPut && len(parts) == 2 && parts[0] == "cards":
+		card := f.getCard(parts[1])
+		if card == nil {
+			break
+		}
+		if q.Get("closed") == "true" {
+			card.Closed = true
+		} else {
+			f.setCardFields(card, q)
+		}
+		response = card
+	}
+
+	if response == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (f *fakeTrello) newID() string {
+	f.nextID++
+	return fmt.Sprintf("id%d", f.nextID)
+}
+
+func (f *fakeTrello) setCardFields(card *Card, q url.Values) {
+	card.Name, card.Desc, card.IDLabels = q.Get("name"), q.Get("desc"), nil
+	if q.Get("idLabels") != "" {
+		card.IDLabels = strings.Split(q.Get("idLabels"), ",")
+	}
+	if start, err := time.Parse(time.RFC3339, q.Get("start")); err == nil {
+		card.Start = &start
+	}
+	if due, err := time.Parse(time.RFC3339, q.Get("due")); err == nil {
+		card.Due = &due
+	}
+}
+
+func (f *fakeTrello) getChecklist(checklistID string) *Checklist {
+	for _, checklists := range f.checklists {
+		for _, c := range checklists {
+			if c.ID == checklistID {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeTrello) getCard(cardID string) *Card {
+	for _, cards := range f.cards {
+		for _, c := range cards {
+			if c.ID == cardID {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
+
+// getCardNames returns the names of the open cards of a list of a board
+func (f *fakeTrello) getCardNames(boardID, listName string) []string {
+	var names []string
+	for _, l := range f.lists[boardID] {
+		if l.Name != listName {
+			continue
+		}
+
+		for _, c := range f.cards[l.ID] {
+			if !c.Closed {
+				names = append(names, c.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+func TestSyncBoard(t *testing.T) {
+	lists := []*ListPlan{
+		{Name: ListVacationWithoutLeaves, Cards: []*CardPlan{
+			{Key: "2024-11-01 - 2024-11-03 ", Name: "2024-11-01 - 2024-11-03 -> 3 days"},
+		}},
+		{Name: ListSuggestions, Cards: []*CardPlan{
+			{Key: "2024-10-03 - 2024-10-06 ", Name: "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days"},
+			{Key: "2024-12-21 - 2025-01-06 ", Name: "2024-12-21 - 2025-01-06 -> 7 leaves / 17 days"},
+		}},
+		{Name: ListOptimalPlan},
+	}
+
+	t.Run("new board", func(t *testing.T) {
+		f, client := newFakeTrello(t)
+		f.boards = append(f.boards, &Board{ID: "other", Name: "Groceries"})
+
+		report, err := client.SyncBoard(context.Background(), "", DefaultBoardName, lists)
+		assert.Nil(t, err)
+		assert.Equal(t, &SyncReport{BoardID: "id1", Created: 3}, report)
+		assert.Equal(t, 2, len(f.lists["id1"]))
+		assert.Equal(t, []string{"2024-11-01 - 2024-11-03 -> 3 days"}, f.getCardNames("id1", ListVacationWithoutLeaves))
+	})
+
+	t.Run("existing board converges", func(t *testing.T) {
+		f, client := newFakeTrello(t)
+		_, err := client.SyncBoard(context.Background(), "", DefaultBoardName, lists)
+		assert.Nil(t, err)
+
+		// the leaves of a suggestion changed, another one is gone and there is a new one
+		changed := []*ListPlan{
+			lists[0],
+			{Name: ListSuggestions, Cards: []*CardPlan{
+				{Key: "2024-10-03 - 2024-10-06 ", Name: "2024-10-03 - 2024-10-06 -> 0 leaves / 4 days"},
+				{Key: "2024-05-09 - 2024-05-12 ", Name: "2024-05-09 - 2024-05-12 -> 1 leaves / 4 days"},
+			}},
+			lists[2],
+		}
+
+		report, err := client.SyncBoard(context.Background(), "", DefaultBoardName, changed)
+		assert.Nil(t, err)
+		assert.Equal(t, &SyncReport{BoardID: "id1", Created: 1, Updated: 1, Archived: 1, Unchanged: 1}, report)
+		assert.Equal(t, 1, len(f.boards))
+		assert.Equal(t, 2, len(f.lists["id1"]))
+		assert.Equal(t, []string{"2024-10-03 - 2024-10-06 -> 0 leaves / 4 days", "2024-05-09 - 2024-05-12 -> 1 leaves / 4 days"},
+			f.getCardNames("id1", ListSuggestions))
+
+		// running again changes nothing
+		report, err = client.SyncBoard(context.Background(), "", DefaultBoardName, changed)
+		assert.Nil(t, err)
+		assert.Equal(t, &SyncReport{BoardID: "id1", Unchanged: 3}, report)
+	})
+
+	t.Run("stored board ID", func(t *testing.T) {
+		f, client := newFakeTrello(t)
+		f.boards = append(f.boards, &Board{ID: "renamed", Name: "My holidays"})
+
+		report, err := client.SyncBoard(context.Background(), "renamed", DefaultBoardName, lists)
+		assert.Nil(t, err)
+		assert.Equal(t, "renamed", report.BoardID)
+		assert.Equal(t, 1, len(f.boards))
+	})
+
+	t.Run("closed board", func(t *testing.T) {
+		f, client := newFakeTrello(t)
+		f.boards = append(f.boards, &Board{ID: "closed", Name: DefaultBoardName, Closed: true})
+
+		report, err := client.SyncBoard(context.Background(), "closed", DefaultBoardName, lists)
+		assert.Equal(t, "board is closed: closed", err.Error())
+		assert.Nil(t, report)
+	})
+
+	t.Run("board not found", func(t *testing.T) {
+		_, client := newFakeTrello(t)
+
+		report, err := client.SyncBoard(context.Background(), "missing", DefaultBoardName, lists)
+		assert.Equal(t, "failed to get board - status code: 404", err.Error())
+		assert.Nil(t, report)
+	})
+
+	t.Run("failed to get boards", func(t *testing.T) {
+		f, client := newFakeTrello(t)
+		f.failPath = "/members/me/boards"
+
+		report, err := client.SyncBoard(context.Background(), "", DefaultBoardName, lists)
+		assert.Equal(t, "failed to get boards - status code: 500", err.Error())
+		assert.Nil(t, report)
+	})
+
+	t.Run("default client", func(t *testing.T) {
+		f, client := newFakeTrello(t)
+		origClient := DefaultClient
+		DefaultClient = client
+		defer func() {
+			DefaultClient = origClient
+		}()
+
+		report, err := SyncBoard("", DefaultBoardName, lists)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, report.Created)
+
+		boardID, err := FindBoard(DefaultBoardName)
+		assert.Nil(t, err)
+		assert.Equal(t, f.boards[0].ID, boardID)
+	})
+}
+
+func TestSyncBoardCardDetails(t *testing.T) {
+	start := time.Date(2024, time.October, 3, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2024, time.October, 6, 12, 0, 0, 0, time.UTC)
+	card := &CardPlan{
+		Key:       "2024-10-03 - 2024-10-06 ",
+		Name:      "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days",
+		Desc:      "Holidays:\n- Thursday, 2024-10-03: German Unity Day",
+		Start:     &start,
+		Due:       &due,
+		Checklist: []string{"2024-10-04"},
+	}
+	lists := []*ListPlan{{Name: ListSuggestions, Cards: []*CardPlan{card}}}
+
+	f, client := newFakeTrello(t)
+	report, err := client.SyncBoard(context.Background(), "", DefaultBoardName, lists)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Created)
+
+	created := f.cards[f.lists["id1"][0].ID][0]
+	assert.Equal(t, card.Desc, created.Desc)
+	assert.True(t, start.Equal(*created.Start))
+	assert.True(t, due.Equal(*created.Due))
+
+	checklist := f.checklists[created.ID][0]
+	assert.Equal(t, ChecklistLeaves, checklist.Name)
+	assert.Equal(t, 1, len(checklist.CheckItems))
+	assert.Equal(t, "2024-10-04", checklist.CheckItems[0].Name)
+
+	t.Run("unchanged card keeps ticked items", func(t *testing.T) {
+		checklist.CheckItems[0].State = CheckItemComplete
+
+		report, err := client.SyncBoard(context.Background(), "", DefaultBoardName, lists)
+		assert.Nil(t, err)
+		assert.Equal(t, &SyncReport{BoardID: "id1", Unchanged: 1}, report)
+		assert.Equal(t, 1, len(f.checklists[created.ID]))
+		assert.Equal(t, CheckItemComplete, checklist.CheckItems[0].State)
+	})
+
+	t.Run("changed leave dates", func(t *testing.T) {
+		changed := *card
+		changed.Desc = "Holidays:\n- Thursday, 2024-10-03: Tag der Deutschen Einheit"
+		changed.Checklist = []string{"2024-10-04", "2024-10-07"}
+
+		report, err := client.SyncBoard(context.Background(), "", DefaultBoardName, []*ListPlan{{Name: ListSuggestions, Cards: []*CardPlan{&changed}}})
+		assert.Nil(t, err)
+		assert.Equal(t, &SyncReport{BoardID: "id1", Updated: 1}, report)
+		assert.Equal(t, changed.Desc, created.Desc)
+		assert.Equal(t, 2, len(checklist.CheckItems))
+		assert.Equal(t, CheckItemComplete, checklist.CheckItems[0].State)
+		assert.Equal(t, "2024-10-07", checklist.CheckItems[1].Name)
+
+		changed.Checklist = []string{"2024-10-07"}
+		_, err = client.SyncBoard(context.Background(), "", DefaultBoardName, []*ListPlan{{Name: ListSuggestions, Cards: []*CardPlan{&changed}}})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(checklist.CheckItems))
+		assert.Equal(t, "2024-10-07", checklist.CheckItems[0].Name)
+	})
+}
+
+func TestSyncBoardLabels(t *testing.T) {
+	excellent := &Label{Name: "excellent", Color: "green"}
+	fair := &Label{Name: "fair", Color: "yellow"}
+	card := &CardPlan{Key: "2024-10-03 - 2024-10-06 ", Name: "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days", Labels: []*Label{excellent}}
+	lists := []*ListPlan{{Name: ListSuggestions, Cards: []*CardPlan{card}, Labels: []*Label{excellent, fair}}}
+
+	f, client := newFakeTrello(t)
+	f.boards = append(f.boards, &Board{ID: "board1", Name: DefaultBoardName})
+	f.labels["board1"] = []*Label{{ID: "unnamed", IDBoard: "board1", Color: "green"}, {ID: "old", IDBoard: "board1", Name: "fair", Color: "red"}}
+
+	report, err := client.SyncBoard(context.Background(), "board1", DefaultBoardName, lists)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 3, len(f.labels["board1"]))
+	assert.Equal(t, "yellow", f.labels["board1"][1].Color)
+	assert.Equal(t, "old", fair.ID)
+
+	created := f.cards[f.lists["board1"][0].ID][0]
+	assert.Equal(t, []string{excellent.ID}, created.IDLabels)
+
+	t.Run("labels added by hand are kept", func(t *testing.T) {
+		created.IDLabels = append(created.IDLabels, "unnamed")
+
+		report, err := client.SyncBoard(context.Background(), "board1", DefaultBoardName, lists)
+		assert.Nil(t, err)
+		assert.Equal(t, &SyncReport{BoardID: "board1", Unchanged: 1}, report)
+		assert.Equal(t, 3, len(f.labels["board1"]))
+	})
+
+	t.Run("changed label", func(t *testing.T) {
+		changed := *card
+		changed.Labels = []*Label{fair}
+
+		report, err := client.SyncBoard(context.Background(), "board1", DefaultBoardName,
+			[]*ListPlan{{Name: ListSuggestions, Cards: []*CardPlan{&changed}, Labels: lists[0].Labels}})
+		assert.Nil(t, err)
+		assert.Equal(t, &SyncReport{BoardID: "board1", Updated: 1}, report)
+		assert.Equal(t, []string{"old", "unnamed"}, created.IDLabels)
+	})
+
+	t.Run("label named after changed settings", func(t *testing.T) {
+		f, client := newFakeTrello(t)
+		f.boards = append(f.boards, &Board{ID: "board1", Name: DefaultBoardName})
+		f.labels["board1"] = []*Label{
+			{ID: "excellent3", IDBoard: "board1", Name: "excellent: ≥3", Color: "green"},
+			{ID: "excellent4", IDBoard: "board1", Name: "excellent: ≥4", Color: "green"},
+			{ID: "hand", IDBoard: "board1", Name: "excellent trip", Color: "purple"},
+		}
+
+		_, err := client.SyncBoard(context.Background(), "board1", DefaultBoardName, lists)
+		assert.Nil(t, err)
+		created := f.cards[f.lists["board1"][0].ID][0]
+		created.IDLabels = []string{"excellent3", "excellent4", "hand"}
+
+		rated := &Label{Name: "excellent: ≥2.5", Color: "green", Prefix: "excellent:"}
+		changed := *card
+		changed.Labels = []*Label{rated}
+
+		report, err := client.SyncBoard(context.Background(), "board1", DefaultBoardName,
+			[]*ListPlan{{Name: ListSuggestions, Cards: []*CardPlan{&changed}, Labels: []*Label{rated}}})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, report.Updated)
+
+		// the first label with the prefix is renamed, the other one is removed from the card
+		assert.Equal(t, "excellent3", rated.ID)
+		assert.Equal(t, "excellent: ≥2.5", f.labels["board1"][0].Name)
+		assert.ElementsMatch(t, []string{"excellent3", "hand"}, created.IDLabels)
+	})
+
+	t.Run("failed to create label", func(t *testing.T) {
+		f, client := newFakeTrello(t)
+		f.failPath = "/labels"
+
+		report, err := client.SyncBoard(context.Background(), "", DefaultBoardName, lists)
+		assert.Equal(t, "failed to create label - status code: 500", err.Error())
+		assert.Nil(t, report)
+	})
+}
@@ -1,146 +1,110 @@
 package trello
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"os"
+	"context"
+	"time"
 )
 
 var (
 	DefaultBoardName          = "Holidays"
 	ListSuggestions           = "Leave suggestions"
 	ListVacationWithoutLeaves = "Vacation without leaves"
-	CreateBoardURL            = "https://api.trello.com/1/boards/"
-	CreateCardURL             = "https://api.trello.com/1/cards"
-	CreateListURL             = "https://api.trello.com/1/boards/%s/lists"
+	ListOptimalPlan           = "Optimal plan"
 
-	trelloAPIKey   = os.Getenv("TRELLO_API_KEY")
-	trelloAPIToken = os.Getenv("TRELLO_API_TOKEN")
+	// DefaultClient is the client of the package-level functions, configured from the environment on first use if nil
+	DefaultClient *Client
 
 	defaultBoardBackground = "sky"
 )
 
-// Response is the structure of the Calendar API's response
-type Response struct {
-	ID string `json:"id"`
+// GetDefaultClient returns DefaultClient, which is created from TRELLO_API_KEY and TRELLO_API_TOKEN if it is nil
+func GetDefaultClient() *Client {
+	if DefaultClient == nil {
+		DefaultClient = NewClient(ConfigFromEnv())
+	}
+
+	return DefaultClient
 }
 
 // CreateBoard creates a board on Trello and returns the board ID
 func CreateBoard(boardName string) (string, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest(http.MethodPost, CreateBoardURL, nil)
-	if err != nil {
-		return "", err
-	}
-
-	q := req.URL.Query()
-	q.Add("key", trelloAPIKey)
-	q.Add("token", trelloAPIToken)
-	q.Add("name", boardName)
-	q.Add("prefs_background", defaultBoardBackground)
-	req.URL.RawQuery = q.Encode()
-
-	res, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-
-	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to create board - status code: %d", res.StatusCode)
-	}
-
-	defer res.Body.Close()
-
-	b, err := io.ReadAll(res.Body)
+	board, err := GetDefaultClient().CreateBoard(context.Background(), boardName)
 	if err != nil {
 		return "", err
 	}
 
-	var response *Response
-	if err := json.Unmarshal(b, &response); err != nil {
-		return "", err
-	}
-
-	return response.ID, nil
+	return board.ID, nil
 }
 
 // CreateList creates a list on Trello and returns the list ID
 func CreateList(boardID, listName, position string) (string, error) {
-	client := &http.Client{}
-	url := fmt.Sprintf(CreateListURL, boardID)
-	req, err := http.NewRequest(http.MethodPost, url, nil)
+	list, err := GetDefaultClient().CreateList(context.Background(), boardID, listName, position)
 	if err != nil {
 		return "", err
 	}
 
-	q := req.URL.Query()
-	q.Add("key", trelloAPIKey)
-	q.Add("token", trelloAPIToken)
-	q.Add("name", listName)
-	q.Add("pos", position) // order of the list
-	req.URL.RawQuery = q.Encode()
+	return list.ID, nil
+}
 
-	res, err := client.Do(req)
+// CreateCard creates a card on Trello and returns the card ID
+func CreateCard(listID, cardName string) (string, error) {
+	card, err := GetDefaultClient().CreateCard(context.Background(), &Card{IDList: listID, Name: cardName})
 	if err != nil {
 		return "", err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to create list - status code: %d", res.StatusCode)
-	}
-
-	defer res.Body.Close()
-
-	b, err := io.ReadAll(res.Body)
-	if err != nil {
-		return "", err
-	}
+	return card.ID, nil
+}
 
-	var response *Response
-	if err := json.Unmarshal(b, &response); err != nil {
-		return "", err
-	}
+// GetBoards returns the open boards of the user
+func GetBoards() ([]*Board, error) {
+	return GetDefaultClient().GetBoards(context.Background())
+}
 
-	return response.ID, nil
+// GetBoard returns a board by ID
+func GetBoard(boardID string) (*Board, error) {
+	return GetDefaultClient().GetBoard(context.Background(), boardID)
 }
 
-// CreateCard creates a card on Trello and returns the card ID
-func CreateCard(listID, cardName string) (string, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest(http.MethodPost, CreateCardURL, nil)
-	if err != nil {
-		return "", err
-	}
+// GetLists returns the open lists of a board, in order
+func GetLists(boardID string) ([]*List, error) {
+	return GetDefaultClient().GetLists(context.Background(), boardID)
+}
 
-	q := req.URL.Query()
-	q.Add("key", trelloAPIKey)
-	q.Add("token", trelloAPIToken)
-	q.Add("name", cardName)
-	q.Add("idList", listID)
-	req.URL.RawQuery = q.Encode()
+// GetCards returns the open cards of a list, in order
+func GetCards(listID string) ([]*Card, error) {
+	return GetDefaultClient().GetCards(context.Background(), listID)
+}
 
-	res, err := client.Do(req)
+// UpdateCard renames a card
+func UpdateCard(cardID, cardName string) error {
+	client := GetDefaultClient()
+	card, err := client.GetCard(context.Background(), cardID)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to create card - status code: %d", res.StatusCode)
-	}
+	card.Name = cardName
+	_, err = client.UpdateCard(context.Background(), card)
+	return err
+}
 
-	defer res.Body.Close()
+// ArchiveCard archives (closes) a card, which can still be restored on Trello
+func ArchiveCard(cardID string) error {
+	return GetDefaultClient().ArchiveCard(context.Background(), cardID)
+}
 
-	b, err := io.ReadAll(res.Body)
-	if err != nil {
-		return "", err
-	}
+// FindBoard returns the ID of the first open board with a name, or an empty string if there is none
+func FindBoard(boardName string) (string, error) {
+	return GetDefaultClient().FindBoard(context.Background(), boardName)
+}
 
-	var response *Response
-	if err := json.Unmarshal(b, &response); err != nil {
-		return "", err
-	}
+// SyncBoard makes a board converge to lists of cards (see Client.SyncBoard)
+func SyncBoard(boardID, boardName string, lists []*ListPlan) (*SyncReport, error) {
+	return GetDefaultClient().SyncBoard(context.Background(), boardID, boardName, lists)
+}
 
-	return response.ID, nil
+// GetBookedLeaves returns the dates of the leaves of the booked cards of a board (see Client.GetBookedLeaves)
+func GetBookedLeaves(boardID, boardName string) ([]time.Time, error) {
+	return GetDefaultClient().GetBookedLeaves(context.Background(), boardID, boardName)
 }
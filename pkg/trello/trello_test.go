@@ -10,10 +10,10 @@ import (
 
 func TestCreateBoard(t *testing.T) {
 	t.Run("invalid URL", func(t *testing.T) {
-		origURL := CreateBoardURL
-		CreateBoardURL = "testInvalidURL%s"
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: "testInvalidURL%"})
 		defer func() {
-			CreateBoardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateBoard(DefaultBoardName)
@@ -22,10 +22,10 @@ func TestCreateBoard(t *testing.T) {
 	})
 
 	t.Run("unsupported protocol", func(t *testing.T) {
-		origURL := CreateBoardURL
-		CreateBoardURL = "testInvalidURL"
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: "testInvalidURL"})
 		defer func() {
-			CreateBoardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateBoard(DefaultBoardName)
@@ -39,10 +39,10 @@ func TestCreateBoard(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		origURL := CreateBoardURL
-		CreateBoardURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateBoardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateBoard(DefaultBoardName)
@@ -56,10 +56,10 @@ func TestCreateBoard(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		origURL := CreateBoardURL
-		CreateBoardURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateBoardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateBoard(DefaultBoardName)
@@ -75,10 +75,10 @@ func TestCreateBoard(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		origURL := CreateBoardURL
-		CreateBoardURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateBoardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateBoard(DefaultBoardName)
@@ -89,10 +89,10 @@ func TestCreateBoard(t *testing.T) {
 
 func TestCreateList(t *testing.T) {
 	t.Run("invalid URL", func(t *testing.T) {
-		origURL := CreateListURL
-		CreateListURL = "testInvalidURL"
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: "testInvalidURL%"})
 		defer func() {
-			CreateListURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateList("abc123a36eaf8d75e160000f", "sample list unauthorized", "1")
@@ -101,10 +101,10 @@ func TestCreateList(t *testing.T) {
 	})
 
 	t.Run("unsupported protocol", func(t *testing.T) {
-		origURL := CreateListURL
-		CreateListURL = "testInvalidURL%s"
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: "testInvalidURL"})
 		defer func() {
-			CreateListURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateList("abc123a36eaf8d75e160000f", "sample list unauthorized", "1")
@@ -118,11 +118,10 @@ func TestCreateList(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		ts.URL = ts.URL + "/%s"
-		origURL := CreateListURL
-		CreateListURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateListURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateList("abc123a36eaf8d75e160000f", "sample list unauthorized", "1")
@@ -136,11 +135,10 @@ func TestCreateList(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		ts.URL = ts.URL + "/%s"
-		origURL := CreateListURL
-		CreateListURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateListURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateList("abc123a36eaf8d75e160000f", "sample list unauthorized", "1")
@@ -156,11 +154,10 @@ func TestCreateList(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		ts.URL = ts.URL + "/%s"
-		origURL := CreateListURL
-		CreateListURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateListURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateList("abc123a36eaf8d75e160000f", "sample list", "1")
@@ -171,10 +168,10 @@ func TestCreateList(t *testing.T) {
 
 func TestCreateCard(t *testing.T) {
 	t.Run("invalid URL", func(t *testing.T) {
-		origURL := CreateCardURL
-		CreateCardURL = "testInvalidURL%s"
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: "testInvalidURL%"})
 		defer func() {
-			CreateCardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateCard("abc123a36ech8d75e160000f", "sample card unauthorized")
@@ -183,10 +180,10 @@ func TestCreateCard(t *testing.T) {
 	})
 
 	t.Run("unsupported protocol", func(t *testing.T) {
-		origURL := CreateCardURL
-		CreateCardURL = "testInvalidURL"
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: "testInvalidURL"})
 		defer func() {
-			CreateCardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateCard("abc123a36ech8d75e160000f", "sample card unauthorized")
@@ -200,10 +197,10 @@ func TestCreateCard(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		origURL := CreateCardURL
-		CreateCardURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateCardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateCard("abc123a36ech8d75e160000f", "sample card unauthorized")
@@ -217,10 +214,10 @@ func TestCreateCard(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		origURL := CreateCardURL
-		CreateCardURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateCardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateCard("abc123a36ech8d75e160000f", "sample card unauthorized")
@@ -236,10 +233,10 @@ func TestCreateCard(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		origURL := CreateCardURL
-		CreateCardURL = ts.URL
+		origClient := DefaultClient
+		DefaultClient = NewClient(Config{BaseURL: ts.URL})
 		defer func() {
-			CreateCardURL = origURL
+			DefaultClient = origClient
 		}()
 
 		result, err := CreateCard("abc123a36ech8d75e160000f", "sample card")
@@ -0,0 +1,122 @@
+package trello
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+var (
+	// ListRequested, ListApproved and ListBooked are the lists that cards are moved to by hand as leaves are requested,
+	// approved and booked. Their cards are not changed by SyncBoard.
+	ListRequested = "Requested"
+	ListApproved  = "Approved"
+	ListBooked    = "Booked"
+
+	leaveDateFormat = "2006-01-02"
+)
+
+// WorkflowLists returns the plans of the workflow lists, which are created if missing and whose cards are left as they are
+func WorkflowLists() []*ListPlan {
+	return []*ListPlan{
+		{Name: ListRequested, Manual: true},
+		{Name: ListApproved, Manual: true},
+		{Name: ListBooked, Manual: true},
+	}
+}
+
+// GetBookedLeaves returns the dates of the leaves of the cards in the ListBooked list of a board, which are the items
+// of their ChecklistLeaves checklist. The board is the one of boardID if given, otherwise the first open board named
+// boardName, and there are no booked leaves if there is none.
+func (c *Client) GetBookedLeaves(ctx context.Context, boardID, boardName string) ([]time.Time, error) {
+	if boardID == "" {
+		var err error
+		if boardID, err = c.FindBoard(ctx, boardName); err != nil || boardID == "" {
+			return nil, err
+		}
+	}
+
+	lists, err := c.GetLists(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	var leaves []time.Time
+	for _, l := range lists {
+		if l.Name != ListBooked {
+			continue
+		}
+
+		cards, err := c.GetCards(ctx, l.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, card := range cards {
+			checklists, err := c.GetChecklists(ctx, card.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			leaves = append(leaves, getLeaveDates(checklists)...)
+		}
+	}
+
+	return leaves, nil
+}
+
+// getLeaveDates returns the items of the ChecklistLeaves checklists that are dates (YYYY-MM-DD)
+func getLeaveDates(checklists []*Checklist) []time.Time {
+	var dates []time.Time
+	for _, c := range checklists {
+		if c.Name != ChecklistLeaves {
+			continue
+		}
+
+		for _, i := range c.CheckItems {
+			if d, err := time.Parse(leaveDateFormat, strings.TrimSpace(i.Name)); err == nil {
+				dates = append(dates, d)
+			}
+		}
+	}
+
+	return dates
+}
+
+// getMovedCards returns the open cards of the manual lists of a board, whose IDs are in the same order as the lists
+func (c *Client) getMovedCards(ctx context.Context, lists []*ListPlan, listIDs []string) ([]*Card, error) {
+	var moved []*Card
+	for i, l := range lists {
+		if !l.Manual || listIDs[i] == "" {
+			continue
+		}
+
+		cards, err := c.GetCards(ctx, listIDs[i])
+		if err != nil {
+			return nil, err
+		}
+		moved = append(moved, cards...)
+	}
+
+	return moved, nil
+}
+
+// withoutMovedCards returns the card plans that match none of the moved cards by name or key
+func withoutMovedCards(cards []*CardPlan, moved []*Card) []*CardPlan {
+	var remaining []*CardPlan
+	for _, p := range cards {
+		isMoved := false
+		for _, m := range moved {
+			if m.Name == p.Name || (p.Key != "" && strings.HasPrefix(m.Name, p.Key)) {
+				isMoved = true
+				break
+			}
+		}
+
+		if !isMoved {
+			remaining = append(remaining, p)
+		}
+	}
+
+	return remaining
+}
@@ -0,0 +1,111 @@
+package trello
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBookedLeaves(t *testing.T) {
+	f, client := newFakeTrello(t)
+	f.boards = append(f.boards, &Board{ID: "board1", Name: DefaultBoardName})
+	f.lists["board1"] = []*List{{ID: "suggestions", Name: ListSuggestions}, {ID: "booked", Name: ListBooked}}
+	f.cards["suggestions"] = []*Card{{ID: "card1", Name: "2024-05-09 - 2024-05-12 -> 1 leaves / 4 days"}}
+	f.cards["booked"] = []*Card{
+		{ID: "card2", Name: "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days"},
+		{ID: "card3", Name: "2024-12-21 - 2025-01-06 -> 7 leaves / 17 days"},
+		{ID: "card4", Name: "2024-11-01 - 2024-11-03 -> 3 days"},
+	}
+	f.checklists["card1"] = []*Checklist{{ID: "checklist1", Name: ChecklistLeaves, CheckItems: []*CheckItem{{Name: "2024-05-10"}}}}
+	f.checklists["card2"] = []*Checklist{{ID: "checklist2", Name: ChecklistLeaves, CheckItems: []*CheckItem{{Name: "2024-10-04"}}}}
+	f.checklists["card3"] = []*Checklist{
+		{ID: "checklist3", Name: ChecklistLeaves, CheckItems: []*CheckItem{{Name: "2024-12-23"}, {Name: "ask the team first"}}},
+		{ID: "checklist4", Name: "Packing", CheckItems: []*CheckItem{{Name: "2024-12-20"}}},
+	}
+
+	expected := []time.Time{time.Date(2024, time.October, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.December, 23, 0, 0, 0, 0, time.UTC)}
+
+	t.Run("by name", func(t *testing.T) {
+		leaves, err := client.GetBookedLeaves(context.Background(), "", DefaultBoardName)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, leaves)
+	})
+
+	t.Run("by ID", func(t *testing.T) {
+		leaves, err := client.GetBookedLeaves(context.Background(), "board1", "Other")
+		assert.Nil(t, err)
+		assert.Equal(t, expected, leaves)
+	})
+
+	t.Run("no board", func(t *testing.T) {
+		leaves, err := client.GetBookedLeaves(context.Background(), "", "Other")
+		assert.Nil(t, err)
+		assert.Empty(t, leaves)
+	})
+
+	t.Run("failed to get checklists", func(t *testing.T) {
+		f.failPath = "/cards/card2/checklists"
+		defer func() {
+			f.failPath = ""
+		}()
+
+		leaves, err := client.GetBookedLeaves(context.Background(), "board1", DefaultBoardName)
+		assert.Equal(t, "failed to get checklists - status code: 500", err.Error())
+		assert.Nil(t, leaves)
+	})
+
+	t.Run("default client", func(t *testing.T) {
+		origClient := DefaultClient
+		DefaultClient = client
+		defer func() {
+			DefaultClient = origClient
+		}()
+
+		leaves, err := GetBookedLeaves("", DefaultBoardName)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, leaves)
+	})
+}
+
+func TestSyncBoardWorkflow(t *testing.T) {
+	lists := append([]*ListPlan{
+		{Name: ListSuggestions, Cards: []*CardPlan{
+			{Key: "2024-10-03 - 2024-10-06 ", Name: "2024-10-03 - 2024-10-06 -> 1 leaves / 4 days"},
+			{Key: "2024-12-21 - 2025-01-06 ", Name: "2024-12-21 - 2025-01-06 -> 7 leaves / 17 days"},
+		}},
+	}, WorkflowLists()...)
+
+	f, client := newFakeTrello(t)
+	report, err := client.SyncBoard(context.Background(), "", DefaultBoardName, lists)
+	assert.Nil(t, err)
+	assert.Equal(t, &SyncReport{BoardID: "id1", Created: 2}, report)
+
+	var names []string
+	for _, l := range f.lists["id1"] {
+		names = append(names, l.Name)
+	}
+	assert.Equal(t, []string{ListSuggestions, ListRequested, ListApproved, ListBooked}, names)
+
+	// a suggestion is requested, and its number of leaves changed since
+	requested := f.lists["id1"][1]
+	card := f.cards[f.lists["id1"][0].ID][0]
+	card.IDList = requested.ID
+	f.cards[requested.ID] = append(f.cards[requested.ID], card)
+	f.cards[f.lists["id1"][0].ID] = f.cards[f.lists["id1"][0].ID][1:]
+
+	changed := append([]*ListPlan{
+		{Name: ListSuggestions, Cards: []*CardPlan{
+			{Key: "2024-10-03 - 2024-10-06 ", Name: "2024-10-03 - 2024-10-06 -> 0 leaves / 4 days"},
+			{Key: "2024-12-21 - 2025-01-06 ", Name: "2024-12-21 - 2025-01-06 -> 7 leaves / 17 days"},
+		}},
+	}, WorkflowLists()...)
+
+	report, err = client.SyncBoard(context.Background(), "", DefaultBoardName, changed)
+	assert.Nil(t, err)
+	assert.Equal(t, &SyncReport{BoardID: "id1", Unchanged: 2}, report)
+	assert.Equal(t, 4, len(f.lists["id1"]))
+	assert.Equal(t, []string{"2024-12-21 - 2025-01-06 -> 7 leaves / 17 days"}, f.getCardNames("id1", ListSuggestions))
+	assert.Equal(t, []string{"2024-10-03 - 2024-10-06 -> 1 leaves / 4 days"}, f.getCardNames("id1", ListRequested))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jvmistica/holiday-planner-go/pkg/trello"
+)
+
+// runUndoCommand runs "undo list" or "undo <runID|last>"
+func runUndoCommand(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("undo", flag.ExitOnError)
+	fs.StringVar(&trello.RunsDir, "runsDir", trello.RunsDir, "the directory of the Trello runs (default: user cache directory)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: holiday-planner-go undo [-runsDir dir] list | <runID> | last")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if fs.Arg(0) == "list" {
+		if err := listRuns(); err != nil {
+			log.Fatalf("failed to list runs - %s", err.Error())
+		}
+		return
+	}
+
+	checkEnv(false, true)
+	if err := undoRun(ctx, fs.Arg(0)); err != nil {
+		log.Fatalf("failed to undo run - %s", describeError(err))
+	}
+}
+
+// listRuns prints the Trello runs that can be undone
+func listRuns() error {
+	runs, err := trello.ListRuns()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tSTARTED AT\tBOARD\tSTATUS\tCREATED")
+	for _, r := range runs {
+		status := "incomplete"
+		if r.Completed {
+			status = "completed"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", r.ID, r.StartedAt.Format("2006-01-02 15:04"), r.BoardID, status, len(r.Created))
+	}
+
+	return w.Flush()
+}
+
+// undoRun removes the boards, lists, cards and labels created by a run
+func undoRun(ctx context.Context, runID string) error {
+	run, err := trello.LoadRun(runID)
+	if err != nil {
+		return err
+	}
+
+	if err := trello.GetDefaultClient().Undo(ctx, run); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: removed %d objects\n", run.ID, len(run.Created))
+	return nil
+}